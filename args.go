@@ -0,0 +1,63 @@
+package paws
+
+import "strings"
+
+// Arg describes a positional argument of a command
+type Arg struct {
+	Name       string // Placeholder name, e.g. "src"
+	HelpText   string // Help description
+	IsOptional bool   // Whether the argument may be omitted
+	IsVariadic bool   // Whether the argument may be repeated
+}
+
+// Positional creates a new required positional argument definition
+func Positional(name string) *Arg {
+	return &Arg{Name: name}
+}
+
+// Help sets the help text for the argument
+func (a *Arg) Help(text string) *Arg {
+	a.HelpText = text
+	return a
+}
+
+// Optional marks the argument as optional
+func (a *Arg) Optional() *Arg {
+	a.IsOptional = true
+	return a
+}
+
+// Variadic marks the argument as repeatable
+func (a *Arg) Variadic() *Arg {
+	a.IsVariadic = true
+	return a
+}
+
+// Usage returns the argument synopsis, e.g. "<src>..." or "[dst]"
+func (a *Arg) Usage() string {
+	s := "<" + a.Name + ">"
+	if a.IsOptional {
+		s = "[" + a.Name + "]"
+	}
+	if a.IsVariadic {
+		s += "..."
+	}
+	return s
+}
+
+// Help sets the description of the command
+func (c *CommandDef) Help(text string) *CommandDef {
+	c.HelpText = text
+	return c
+}
+
+// Args appends positional argument definitions to the command
+func (c *CommandDef) Args(args ...*Arg) *CommandDef {
+	c.Positionals = append(c.Positionals, args...)
+	return c
+}
+
+// Name returns the command path joined by spaces
+func (c *CommandDef) Name() string {
+	return strings.Join(c.Path, " ")
+}
@@ -0,0 +1,83 @@
+package paws
+
+import (
+	"slices"
+	"strings"
+)
+
+// ReconstructOptions controls the arguments built by Reconstruct
+type ReconstructOptions struct {
+	Defaults bool // Include flags left at a non-zero default value
+	CLIOnly  bool // Leave out values from the environment, config files and prompts
+	Redact   bool // Replace the values of secret flags, for display and logs
+}
+
+// Reconstruct turns r back into canonical arguments: the command path,
+// flags in definition order as "--name=value" with aliases resolved, then
+// positionals, after "--" when one could be read as a flag or command.
+// True bools are written as "--name", false ones as "--name=false".
+// Each flag holds a single value, so it appears at most once.
+// Parsing the arguments again yields an equivalent result.
+func (r *ParseResult) Reconstruct(opts ReconstructOptions) []string {
+	var args []string
+	if r.Command != nil {
+		args = append(args, r.Command.Path...)
+	}
+
+	for _, f := range r.scopeFlags() {
+		value, ok := r.Flags[f.Name]
+		switch {
+		case ok && (!opts.CLIOnly || r.Source(f.Name).Source == SourceCLI):
+		case !ok && opts.Defaults && defaultString(f) != "":
+			value = defaultString(f)
+		default:
+			continue
+		}
+
+		if f.Type == BoolType && parseBoolValue(value) {
+			args = append(args, "--"+f.Name)
+			continue
+		}
+		if f.Type == BoolType {
+			value = "false"
+		}
+		if opts.Redact {
+			value = shownValue(f, value)
+		}
+		args = append(args, "--"+f.Name+"="+value)
+	}
+	args = append(args, r.Unknown...)
+
+	if len(r.Rest) > 0 {
+		return append(args, r.Rest...)
+	}
+	if r.needsDoubleDash(args) {
+		args = append(args, "--")
+	}
+	return append(args, r.Positional...)
+}
+
+// needsDoubleDash reports whether the positionals of r, following args,
+// would be read as flags or command words without "--"
+func (r *ParseResult) needsDoubleDash(args []string) bool {
+	if slices.ContainsFunc(r.Positional, func(p string) bool { return strings.HasPrefix(p, "-") && p != "-" }) {
+		return true
+	}
+	if len(r.Positional) == 0 || r.index == nil {
+		return false
+	}
+	cmd, _, err := r.index.findCommand(append(slices.Clip(args), r.Positional...))
+	return err != nil || cmd != r.Command
+}
+
+// scopeFlags returns the global flags followed by the flags of the matched command
+func (r *ParseResult) scopeFlags() []*Flag {
+	var flags []*Flag
+	if r.index != nil {
+		flags = r.index.p.Flags
+	}
+	if r.Command != nil {
+		flags = append(slices.Clip(flags), r.Command.Flags...)
+	}
+	return flags
+}
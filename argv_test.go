@@ -0,0 +1,108 @@
+package paws
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReconstruct(t *testing.T) {
+	parser := New()
+	parser.AddFlags(
+		Paw[bool]("verbose", "v"),
+		Paw[bool]("color").Default(true),
+		Paw[string]("log-level", "l").Default("info"),
+	)
+	parser.AddCommand([]string{"remote"}, []*Flag{Paw[string]("name", "n")})
+	parser.AddCommand([]string{"remote", "add"}, []*Flag{
+		Paw[int]("depth", "d"),
+		Paw[string]("x"),
+		Paw[string]("mode").Choices("fast", "slow"),
+	})
+
+	tests := []struct {
+		name string
+		args []string
+		opts ReconstructOptions
+		want []string
+	}{
+		{"empty", nil, ReconstructOptions{}, nil},
+		{"aliases", []string{"remote", "add", "-vd", "3", "origin"}, ReconstructOptions{}, []string{"remote", "add", "--verbose", "--depth=3", "origin"}},
+		{"definition order", []string{"remote", "add", "--mode", "slow", "-l", "debug", "url"}, ReconstructOptions{}, []string{"remote", "add", "--log-level=debug", "--mode=slow", "url"}},
+		{"false bool", []string{"remote", "--color=false"}, ReconstructOptions{}, []string{"remote", "--color=false"}},
+		{"last value wins", []string{"remote", "-n", "a", "--name", "b"}, ReconstructOptions{}, []string{"remote", "--name=b"}},
+		{"dash values", []string{"remote", "add", "-d", "-5", "--x=-y"}, ReconstructOptions{}, []string{"remote", "add", "--depth=-5", "--x=-y"}},
+		{"empty value", []string{"remote", "add", "--x="}, ReconstructOptions{}, []string{"remote", "add", "--x="}},
+		{"dash positional", []string{"remote", "add", "--", "-f", "x"}, ReconstructOptions{}, []string{"remote", "add", "--", "-f", "x"}},
+		{"negative positional", []string{"remote", "add", "-7"}, ReconstructOptions{}, []string{"remote", "add", "--", "-7"}},
+		{"command word positional", []string{"remote", "--", "add"}, ReconstructOptions{}, []string{"remote", "--", "add"}},
+		{"command word after flag", []string{"remote", "-n", "a", "--", "add"}, ReconstructOptions{}, []string{"remote", "--name=a", "add"}},
+		{"stdin positional", []string{"remote", "add", "-"}, ReconstructOptions{}, []string{"remote", "add", "-"}},
+		{"defaults", []string{"remote"}, ReconstructOptions{Defaults: true}, []string{"remote", "--color", "--log-level=info"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := parser.Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.args, err)
+			}
+
+			got := r.Reconstruct(tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Reconstruct() = %q, want %q", got, tt.want)
+			}
+
+			again, err := parser.Parse(got)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", got, err)
+			}
+			if again.Command != r.Command {
+				t.Errorf("round trip command = %v, want %v", again.Command, r.Command)
+			}
+			if !reflect.DeepEqual(again.Positional, r.Positional) {
+				t.Errorf("round trip positional = %q, want %q", again.Positional, r.Positional)
+			}
+			for name, value := range r.Flags {
+				if again.Flags[name] != value {
+					t.Errorf("round trip %s = %q, want %q", name, again.Flags[name], value)
+				}
+			}
+		})
+	}
+}
+
+func TestReconstructSources(t *testing.T) {
+	t.Setenv("ARGV_TOKEN", "abc")
+	parser := New()
+	parser.AddFlags(Paw[string]("token").Env("ARGV_TOKEN"), Paw[int]("retries"))
+
+	r, err := parser.Parse([]string{"--retries", "3"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := r.ApplyConfig(map[string]string{"retries": "2"}, "app.toml"); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+
+	if got, want := r.Reconstruct(ReconstructOptions{}), []string{"--token=abc", "--retries=3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconstruct() = %q, want %q", got, want)
+	}
+	if got, want := r.Reconstruct(ReconstructOptions{CLIOnly: true}), []string{"--retries=3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconstruct(CLIOnly) = %q, want %q", got, want)
+	}
+}
+
+func TestReconstructRest(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[bool]("verbose", "v"))
+	parser.AddCommand([]string{"exec"}, nil).Interspersal = NonInterspersed
+
+	r, err := parser.Parse([]string{"exec", "-v", "ls", "-la", "--", "x"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []string{"exec", "--verbose", "ls", "-la", "--", "x"}
+	if got := r.Reconstruct(ReconstructOptions{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconstruct() = %q, want %q", got, want)
+	}
+}
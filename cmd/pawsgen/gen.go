@@ -0,0 +1,462 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/fyrna/paws"
+)
+
+// goTypes maps spec type names to the Go type of their values
+var goTypes = map[string]string{
+	"bool":   "bool",
+	"string": "string",
+	"int":    "int",
+	"uint":   "uint",
+	"float":  "float64",
+	"path":   "string",
+	"file":   "string",
+	"enum":   "string",
+	"int8":   "int8",
+	"int16":  "int16",
+	"int32":  "int32",
+	"int64":  "int64",
+	"uint8":  "uint8",
+	"uint16": "uint16",
+	"uint32": "uint32",
+	"uint64": "uint64",
+}
+
+// fileChecks lists the checks InFile and OutFile add by themselves
+var fileChecks = map[string][]string{
+	"read":  {"file", "readable"},
+	"write": {"writable"},
+}
+
+// checkConsts maps spec check names to their paws constants
+var checkConsts = map[string]string{
+	"exists":     "paws.PathExists",
+	"not-exists": "paws.PathNotExists",
+	"file":       "paws.PathIsFile",
+	"dir":        "paws.PathIsDir",
+	"readable":   "paws.PathReadable",
+	"writable":   "paws.PathWritable",
+}
+
+// generate returns the formatted Go code for the spec
+func generate(spec *paws.Spec, pkg string) ([]byte, error) {
+	// Building the parser validates the spec
+	if _, err := spec.Parser(); err != nil {
+		return nil, err
+	}
+
+	g := &generator{}
+	g.printf("// Code generated by pawsgen. DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", pkg)
+	g.printf("import (\n\"errors\"\n")
+	if len(spec.Commands) > 0 {
+		g.printf("\"fmt\"\n")
+	}
+	g.printf("\n\"github.com/fyrna/paws\"\n)\n\n")
+
+	if err := g.register(spec); err != nil {
+		return nil, err
+	}
+	if err := g.options("GlobalOptions", "the global flags", nil, spec.Flags, false); err != nil {
+		return nil, err
+	}
+	for _, cmd := range spec.Commands {
+		name := typeName(cmd.Path) + "Options"
+		if err := g.options(name, fmt.Sprintf("the flags of %q", strings.Join(cmd.Path, " ")), cmd.Path, cmd.Flags, true); err != nil {
+			return nil, err
+		}
+	}
+
+	g.printf(`// pawsgenGet stores the value of a flag in dst or appends the error to errs
+func pawsgenGet[T paws.FlagTypeConstraint](r *paws.ParseResult, name string, dst *T, errs *[]error) {
+	v, err := paws.Get[T](r, name)
+	if err != nil {
+		*errs = append(*errs, err)
+		return
+	}
+	*dst = v
+}
+`)
+
+	code, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return code, nil
+}
+
+type generator struct {
+	buf bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// register writes the Commands type and the Register function
+func (g *generator) register(spec *paws.Spec) error {
+	names := make(map[string]bool)
+
+	g.printf("// Commands holds the commands added by Register\ntype Commands struct {\n")
+	for _, cmd := range spec.Commands {
+		name := typeName(cmd.Path)
+		if names[name] {
+			return fmt.Errorf("commands %q and another one share the Go name %s", strings.Join(cmd.Path, " "), name)
+		}
+		names[name] = true
+		g.printf("%s *paws.CommandDef\n", name)
+	}
+	g.printf("}\n\n")
+
+	g.printf("// Register adds the global flags and commands to p\nfunc Register(p *paws.Parser) *Commands {\n")
+	for _, s := range []struct {
+		set  bool
+		stmt string
+	}{
+		{spec.AllowAbbrev, "p.AllowAbbrev = true"},
+		{spec.AllowUnknown, "p.AllowUnknown = true"},
+		{spec.IntLiterals, "p.IntLiterals = true"},
+		{spec.PosixlyCorrect, "p.PosixlyCorrect = true"},
+		{spec.Strict, "p.Strict = true"},
+		{spec.Interspersal != "", "p.Interspersal = " + interspersal(spec.Interspersal)},
+	} {
+		if s.set {
+			g.printf("%s\n", s.stmt)
+		}
+	}
+
+	g.printf("\nc := &Commands{}\n")
+	if len(spec.Flags) > 0 || slices.ContainsFunc(spec.Commands, func(c paws.CommandSpec) bool { return len(c.Flags) > 0 }) {
+		g.printf("var flags []*paws.Flag\n")
+	}
+	if len(spec.Flags) > 0 {
+		if err := g.flags(spec.Flags); err != nil {
+			return err
+		}
+		g.printf("p.AddFlags(flags...)\n")
+	}
+
+	for _, cmd := range spec.Commands {
+		g.printf("\n")
+		flags := "nil"
+		if len(cmd.Flags) > 0 {
+			if err := g.flags(cmd.Flags); err != nil {
+				return err
+			}
+			flags = "flags"
+		}
+
+		field := "c." + typeName(cmd.Path)
+		g.printf("%s = p.AddCommand(%s, %s)", field, stringSlice(cmd.Path), flags)
+		if cmd.Help != "" {
+			g.printf(".Help(%q)", cmd.Help)
+		}
+		if len(cmd.Args) > 0 {
+			g.printf(".Args(\n")
+			for _, a := range cmd.Args {
+				g.printf("paws.Positional(%q)", a.Name)
+				if a.Help != "" {
+					g.printf(".Help(%q)", a.Help)
+				}
+				if a.Optional {
+					g.printf(".Optional()")
+				}
+				if a.Variadic {
+					g.printf(".Variadic()")
+				}
+				g.printf(",\n")
+			}
+			g.printf(")")
+		}
+		g.printf("\n")
+		if cmd.Interspersal != "" {
+			g.printf("%s.Interspersal = %s\n", field, interspersal(cmd.Interspersal))
+		}
+	}
+
+	g.printf("return c\n}\n\n")
+	return nil
+}
+
+// flags writes the assignment of a flag list to the flags variable
+func (g *generator) flags(specs []paws.FlagSpec) error {
+	var fixes []string
+
+	g.printf("flags = []*paws.Flag{\n")
+	for i, f := range specs {
+		expr, fix, err := flagExpr(f)
+		if err != nil {
+			return fmt.Errorf("flag %s: %w", f.Name, err)
+		}
+		g.printf("%s,\n", expr)
+		if fix != "" {
+			fixes = append(fixes, fmt.Sprintf("flags[%d].PathCheck = %s", i, fix))
+		}
+	}
+	g.printf("}\n")
+	for _, fix := range fixes {
+		g.printf("%s\n", fix)
+	}
+	return nil
+}
+
+// flagExpr returns the builder expression of a flag, and the full PathCheck
+// value to assign when the constructor adds checks the spec does not have
+func flagExpr(f paws.FlagSpec) (expr, fix string, err error) {
+	var b strings.Builder
+	names := strings.Join(quoteAll(append([]string{f.Name}, f.Aliases...)), ", ")
+
+	typ, ok := goTypes[f.Type]
+	if !ok {
+		return "", "", fmt.Errorf("unknown type %q", f.Type)
+	}
+
+	checks := f.Check
+	switch f.Type {
+	case "path":
+		fmt.Fprintf(&b, "paws.Path(%s)", names)
+	case "file":
+		mode := f.Mode
+		if mode == "" {
+			mode = "read"
+		}
+		ctor := map[string]string{"read": "paws.InFile", "write": "paws.OutFile"}[mode]
+		fmt.Fprintf(&b, "%s(%s)", ctor, names)
+
+		var extra []string
+		for _, c := range checks {
+			if !slices.Contains(fileChecks[mode], c) {
+				extra = append(extra, c)
+			}
+		}
+		for _, c := range fileChecks[mode] {
+			if !slices.Contains(checks, c) {
+				fix = checkExpr(checks)
+			}
+		}
+		checks = extra
+	case "enum":
+		fmt.Fprintf(&b, "paws.Enum(%q, []paws.EnumValue[string]{\n", f.Name)
+		for _, o := range f.Enum {
+			fmt.Fprintf(&b, "{Token: %q", o.Token)
+			if len(o.Aliases) > 0 {
+				fmt.Fprintf(&b, ", Aliases: %s", stringSlice(o.Aliases))
+			}
+			fmt.Fprintf(&b, ", Value: %q", o.Token)
+			if o.Desc != "" {
+				fmt.Fprintf(&b, ", Desc: %q", o.Desc)
+			}
+			b.WriteString("},\n")
+		}
+		b.WriteString("}")
+		for _, a := range f.Aliases {
+			fmt.Fprintf(&b, ", %q", a)
+		}
+		b.WriteString(")")
+	default:
+		fmt.Fprintf(&b, "paws.Paw[%s](%s)", typ, names)
+	}
+
+	if len(f.Default) > 0 {
+		def, err := defaultExpr(typ, f.Default)
+		if err != nil {
+			return "", "", err
+		}
+		fmt.Fprintf(&b, ".Default(%s)", def)
+	}
+	if f.Required {
+		b.WriteString(".Required()")
+	}
+	if f.Help != "" {
+		fmt.Fprintf(&b, ".Help(%q)", f.Help)
+	}
+	if f.Meta != "" {
+		fmt.Fprintf(&b, ".Meta(%q)", f.Meta)
+	}
+	if len(f.Choices) > 0 {
+		fmt.Fprintf(&b, ".Choices(%s)", strings.Join(quoteAll(f.Choices), ", "))
+	}
+	if f.IgnoreCase {
+		b.WriteString(".IgnoreCase()")
+	}
+	if m := f.Min; m != nil {
+		method := "AtLeast"
+		if m.Exclusive {
+			method = "Above"
+		}
+		fmt.Fprintf(&b, ".%s(%s)", method, formatFloat(m.Value))
+	}
+	if m := f.Max; m != nil {
+		method := "AtMost"
+		if m.Exclusive {
+			method = "Below"
+		}
+		fmt.Fprintf(&b, ".%s(%s)", method, formatFloat(m.Value))
+	}
+	if len(f.Env) > 0 {
+		fmt.Fprintf(&b, ".Env(%s)", strings.Join(quoteAll(f.Env), ", "))
+	}
+	if f.Optional {
+		fmt.Fprintf(&b, ".Optional(%q)", f.Implied)
+	}
+	if f.AllowHyphen {
+		b.WriteString(".AllowHyphenValues()")
+	}
+	if f.IntLiterals {
+		b.WriteString(".AcceptLiterals()")
+	}
+	if len(checks) > 0 && fix == "" {
+		fmt.Fprintf(&b, ".Check(%s)", checkExpr(checks))
+	}
+	if f.Absolute {
+		b.WriteString(".Absolute()")
+	}
+	if f.Secret {
+		b.WriteString(".Secret()")
+	}
+	if f.FromFile {
+		b.WriteString(".FromFile()")
+	}
+	return b.String(), fix, nil
+}
+
+// options writes an options struct and its constructor
+func (g *generator) options(name, what string, path []string, specs []paws.FlagSpec, command bool) error {
+	fields := make(map[string]string)
+	reserved := []string{"GlobalOptions", "Args"}
+
+	g.printf("// %s holds %s\ntype %s struct {\n", name, what, name)
+	if command {
+		g.printf("GlobalOptions\n\n")
+	}
+	for _, f := range specs {
+		field := fieldName(f.Name)
+		if other, ok := fields[field]; ok || slices.Contains(reserved, field) {
+			return fmt.Errorf("flag %s and %s share the Go name %s", f.Name, other, field)
+		}
+		fields[field] = f.Name
+
+		comment := ""
+		if f.Help != "" {
+			comment = " // " + strings.ReplaceAll(f.Help, "\n", " ")
+		}
+		g.printf("%s %s%s\n", field, goTypes[f.Type], comment)
+	}
+	if command {
+		g.printf("\nArgs []string // Positional arguments\n")
+	}
+	g.printf("}\n\n")
+
+	if !command {
+		g.printf("// New%s reads %s from a parse result\n", name, what)
+		g.printf("func New%s(r *paws.ParseResult) (*%s, error) {\nvar o %s\nreturn &o, o.load(r)\n}\n\n", name, name, name)
+		g.printf("func (o *%s) load(r *paws.ParseResult) error {\nvar errs []error\n", name)
+		for _, f := range specs {
+			g.printf("pawsgenGet(r, %q, &o.%s, &errs)\n", f.Name, fieldName(f.Name))
+		}
+		g.printf("return errors.Join(errs...)\n}\n\n")
+		return nil
+	}
+
+	g.printf("// New%s reads %s from a parse result\n", name, what)
+	g.printf("func New%s(r *paws.ParseResult) (*%s, error) {\n", name, name)
+	g.printf("if r.Command == nil || r.Command.Name() != %q {\n", strings.Join(path, " "))
+	g.printf("return nil, fmt.Errorf(\"parse result is not for command %%q\", %q)\n}\n\n", strings.Join(path, " "))
+	g.printf("var o %s\nerrs := []error{o.GlobalOptions.load(r)}\n", name)
+	for _, f := range specs {
+		g.printf("pawsgenGet(r, %q, &o.%s, &errs)\n", f.Name, fieldName(f.Name))
+	}
+	g.printf("o.Args = r.Positional\nreturn &o, errors.Join(errs...)\n}\n\n")
+	return nil
+}
+
+// defaultExpr returns a Go expression of the flag type for a JSON default
+func defaultExpr(typ string, raw json.RawMessage) (string, error) {
+	switch typ {
+	case "string":
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+		return strconv.Quote(s), nil
+	case "bool", "int":
+		return string(raw), nil
+	}
+	return fmt.Sprintf("%s(%s)", typ, raw), nil
+}
+
+// typeName returns the exported Go name of a command path, e.g. "RemoteAdd"
+func typeName(path []string) string {
+	var b strings.Builder
+	for _, w := range path {
+		b.WriteString(fieldName(w))
+	}
+	return b.String()
+}
+
+// fieldName returns the exported Go name of a flag, e.g. "dry-run" becomes "DryRun"
+func fieldName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "F" + s
+	}
+	return s
+}
+
+func interspersal(name string) string {
+	if name == "non-interspersed" {
+		return "paws.NonInterspersed"
+	}
+	return "paws.Interspersed"
+}
+
+func checkExpr(checks []string) string {
+	if len(checks) == 0 {
+		return "0"
+	}
+	var consts []string
+	for _, c := range checks {
+		consts = append(consts, checkConsts[c])
+	}
+	return strings.Join(consts, " | ")
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func stringSlice(s []string) string {
+	return "[]string{" + strings.Join(quoteAll(s), ", ") + "}"
+}
+
+func quoteAll(s []string) []string {
+	q := make([]string, len(s))
+	for i, v := range s {
+		q[i] = strconv.Quote(v)
+	}
+	return q
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fyrna/paws"
+)
+
+const testSpec = `{
+  "version": 1,
+  "allow_abbrev": true,
+  "flags": [
+    {"name": "verbose", "aliases": ["v"], "type": "bool", "help": "verbose output"}
+  ],
+  "commands": [
+    {
+      "path": ["remote", "add"],
+      "help": "Add a remote",
+      "flags": [
+        {"name": "dry-run", "type": "bool"},
+        {"name": "depth", "type": "int8", "default": 3, "min": {"value": 1}, "max": {"value": 10}},
+        {"name": "ratio", "type": "float", "default": 2, "max": {"value": 5, "exclusive": true}},
+        {"name": "mode", "type": "enum", "enum": [{"token": "fetch", "desc": "fetch only"}, {"token": "push"}], "default": "fetch"},
+        {"name": "url", "type": "string", "required": true, "choices": ["a", "b"]},
+        {"name": "key", "type": "file", "mode": "read", "check": ["readable"]},
+        {"name": "token", "type": "string", "secret": true, "from_file": true}
+      ],
+      "args": [{"name": "name"}, {"name": "rest", "optional": true, "variadic": true}]
+    },
+    {"path": ["list"], "interspersal": "non-interspersed"}
+  ]
+}`
+
+func generateSpec(t *testing.T, spec string) []byte {
+	t.Helper()
+	s, err := paws.ReadSpec(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("ReadSpec() error = %v", err)
+	}
+	code, err := generate(s, "cli")
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	return code
+}
+
+func TestGenerate(t *testing.T) {
+	code := string(generateSpec(t, testSpec))
+
+	for _, want := range []string{
+		"// Code generated by pawsgen. DO NOT EDIT.",
+		"package cli",
+		"p.AllowAbbrev = true",
+		`paws.Paw[int8]("depth").Default(int8(3)).AtLeast(1).AtMost(10)`,
+		`paws.Paw[float64]("ratio").Default(float64(2)).Below(5)`,
+		`{Token: "fetch", Value: "fetch", Desc: "fetch only"}`,
+		`paws.Paw[string]("url").Required().Choices("a", "b")`,
+		`paws.InFile("key")`,
+		"flags[5].PathCheck = paws.PathReadable",
+		`paws.Paw[string]("token").Secret().FromFile()`,
+		`c.List = p.AddCommand([]string{"list"}, nil)`,
+		"c.List.Interspersal = paws.NonInterspersed",
+		"type RemoteAddOptions struct {",
+		"DryRun bool",
+		"Depth  int8",
+		"func NewRemoteAddOptions(r *paws.ParseResult) (*RemoteAddOptions, error) {",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generate() missing %q in:\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateNameClash(t *testing.T) {
+	spec := `{"version": 1, "commands": [{"path": ["a"], "flags": [{"name": "dry-run", "type": "bool"}, {"name": "dry_run", "type": "bool"}]}]}`
+	s, err := paws.ReadSpec(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("ReadSpec() error = %v", err)
+	}
+	if _, err := generate(s, "cli"); err == nil || !strings.Contains(err.Error(), "DryRun") {
+		t.Errorf("generate() error = %v, want name clash", err)
+	}
+}
+
+const testMain = `package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fyrna/paws"
+)
+
+func main() {
+	p := paws.New()
+	cmds := Register(p)
+	r, err := p.Parse(os.Args[1:])
+	if err != nil || r.Command != cmds.RemoteAdd {
+		fmt.Println("parse:", err)
+		os.Exit(1)
+	}
+	o, err := NewRemoteAddOptions(r)
+	fmt.Println(o.Verbose, o.DryRun, o.Depth, o.Ratio, o.Mode, o.Url, o.Args, err)
+	if _, err := NewListOptions(r); err == nil {
+		os.Exit(1)
+	}
+}
+`
+
+func TestGeneratedCodeBuilds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a module")
+	}
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := generateSpec(t, testSpec)
+	code = []byte(strings.Replace(string(code), "package cli", "package main", 1))
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":     "module gen\n\ngo 1.25.0\n\nrequire github.com/fyrna/paws v0.0.0\n\nreplace github.com/fyrna/paws => " + root + "\n",
+		"cli_gen.go": string(code),
+		"main.go":    testMain,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command("go", "run", ".", "remote", "add", "-v", "--dry-run", "--url", "b", "origin")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run error = %v\n%s", err, out)
+	}
+	if got, want := strings.TrimSpace(string(out)), "true true 3 2 fetch b [origin] <nil>"; got != want {
+		t.Errorf("generated program printed %q, want %q", got, want)
+	}
+}
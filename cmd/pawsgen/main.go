@@ -0,0 +1,63 @@
+// Command pawsgen generates typed option structs from a paws JSON spec.
+//
+// Usage with go generate:
+//
+//	//go:generate go run github.com/fyrna/paws/cmd/pawsgen --spec cli.json -o cli_gen.go
+//
+// The generated file holds a Register function adding the flags and
+// commands of the spec to a parser, an options struct per command and
+// constructors reading them from a parse result.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fyrna/paws"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "pawsgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the arguments and writes the generated code
+func run(args []string, stdout io.Writer) error {
+	p := paws.New()
+	p.AddFlags(
+		paws.InFile("spec", "s").Help("JSON spec to read, - for stdin").Required(),
+		paws.Paw[string]("output", "o").Meta("FILE").Help("file to write, stdout when empty"),
+		paws.Paw[string]("package", "p").Meta("NAME").Env("GOPACKAGE").Default("main").Help("package of the generated code"),
+	)
+
+	result, err := p.Parse(args)
+	if err != nil {
+		return err
+	}
+	if err := p.ValidateRequired(result); err != nil {
+		return err
+	}
+
+	in, err := result.File("spec")
+	if err != nil {
+		return err
+	}
+	spec, err := paws.ReadSpec(in)
+	if err != nil {
+		return err
+	}
+
+	code, err := generate(spec, result.String("package"))
+	if err != nil {
+		return err
+	}
+
+	if out := result.String("output"); out != "" {
+		return os.WriteFile(out, code, 0o644)
+	}
+	_, err = stdout.Write(code)
+	return err
+}
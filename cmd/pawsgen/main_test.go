@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Setenv("GOPACKAGE", "cli")
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "cli.json")
+	if err := os.WriteFile(spec, []byte(testSpec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want := generateSpec(t, testSpec)
+
+	// The form documented for go generate
+	out := filepath.Join(dir, "cli_gen.go")
+	var stdout bytes.Buffer
+	if err := run([]string{"--spec", spec, "-o", out}, &stdout); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if got, err := os.ReadFile(out); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("run() wrote %q, %v, want the generated code", got, err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("run() wrote to stdout with -o: %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := run([]string{"-s", spec}, &stdout); err != nil {
+		t.Fatalf("run(-s) error = %v", err)
+	}
+	if !bytes.Equal(stdout.Bytes(), want) {
+		t.Errorf("run(-s) = %q, want the generated code", stdout.String())
+	}
+
+	// A single dash reads as -s with the value "pec"
+	if err := run([]string{"-spec", spec}, &stdout); err == nil {
+		t.Error("run(-spec) error = nil")
+	}
+}
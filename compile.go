@@ -0,0 +1,244 @@
+package paws
+
+import (
+	"slices"
+	"strings"
+)
+
+// Compiled is a read-only parser with prebuilt indexes.
+// It is safe for concurrent use as long as the flag and command
+// definitions it was compiled from are not modified afterwards.
+type Compiled struct {
+	p        *Parser                       // Snapshot of the parser settings
+	global   map[string]*Flag              // Global flags by name and alias
+	root     *commandIndex                 // Index used when no command matched
+	commands map[*CommandDef]*commandIndex // Per-command indexes
+	trie     *commandNode                  // Command paths
+}
+
+// commandIndex holds the flag lookups for one command
+type commandIndex struct {
+	flags  map[string]*Flag // Command flags by name and alias
+	long   []string         // Global and command long names, sorted for abbreviation
+	digits bool             // Whether a digit short flag exists
+
+	defs []*Flag  // Command flags the index was built from
+	path []string // Command path the trie was built from
+}
+
+// commandNode is a trie node over command path words
+type commandNode struct {
+	cmd      *CommandDef
+	children map[string]*commandNode
+	words    []string // Sorted child words, for abbreviation
+}
+
+// Compile builds a read-only parser from the current definitions
+func (p *Parser) Compile() *Compiled {
+	snapshot := *p
+	snapshot.Flags = slices.Clone(p.Flags)
+	snapshot.Commands = slices.Clone(p.Commands)
+	snapshot.cache = nil
+	c := &Compiled{
+		p:        &snapshot,
+		global:   indexFlags(p.Flags, true),
+		commands: make(map[*CommandDef]*commandIndex, len(p.Commands)),
+		trie:     &commandNode{},
+	}
+
+	c.root = c.buildIndex(nil)
+	for _, cmd := range p.Commands {
+		if _, ok := c.commands[cmd]; !ok {
+			c.commands[cmd] = c.buildIndex(cmd)
+		}
+		c.trie.insert(cmd)
+	}
+	return c
+}
+
+// ValidateRequired checks if all required flags are provided
+func (c *Compiled) ValidateRequired(result *ParseResult) error {
+	return c.p.ValidateRequired(result)
+}
+
+// current reports whether c was compiled from the flags, commands and
+// settings p holds now
+func (c *Compiled) current(p *Parser) bool {
+	q := c.p
+	if q.AllowAbbrev != p.AllowAbbrev || q.AllowUnknown != p.AllowUnknown || q.Strict != p.Strict ||
+		q.IntLiterals != p.IntLiterals || q.Interspersal != p.Interspersal || q.PosixlyCorrect != p.PosixlyCorrect {
+		return false
+	}
+	if !slices.Equal(q.Flags, p.Flags) || !slices.Equal(q.Commands, p.Commands) {
+		return false
+	}
+	for _, cmd := range p.Commands {
+		idx := c.commands[cmd]
+		if !slices.Equal(idx.defs, cmd.Flags) || !slices.Equal(idx.path, cmd.Path) {
+			return false
+		}
+	}
+	return true
+}
+
+// indexFlags maps flag names and aliases to their definitions, followed
+// by the companions of flags read from files. When names collide, later
+// flags replace earlier ones if replace is set, global flags have always
+// resolved that way while the first command flag was kept.
+func indexFlags(flags []*Flag, replace bool) map[string]*Flag {
+	n := 0
+	for _, f := range flags {
+		n += 1 + len(f.Aliases)
+	}
+
+	m := make(map[string]*Flag, n)
+	for _, f := range flags {
+		for _, name := range append([]string{f.Name}, f.Aliases...) {
+			if _, ok := m[name]; replace || !ok {
+				m[name] = f
+			}
+		}
+	}
+	for _, f := range flags {
+		if _, ok := m[f.fileFlagName()]; f.FileFlag && !ok {
+			m[f.fileFlagName()] = f.fileFlag()
+		}
+	}
+	return m
+}
+
+// buildIndex creates the flag index for cmd, or for no command when nil
+func (c *Compiled) buildIndex(cmd *CommandDef) *commandIndex {
+	idx := &commandIndex{}
+	if cmd != nil {
+		idx.flags = indexFlags(cmd.Flags, false)
+		idx.defs = slices.Clone(cmd.Flags)
+		idx.path = slices.Clone(cmd.Path)
+	}
+
+	for _, m := range []map[string]*Flag{c.global, idx.flags} {
+		for name := range m {
+			if len(name) > 1 && !slices.Contains(idx.long, name) {
+				idx.long = append(idx.long, name)
+			}
+			if len(name) == 1 && name[0] >= '0' && name[0] <= '9' {
+				idx.digits = true
+			}
+		}
+	}
+	slices.Sort(idx.long)
+	return idx
+}
+
+// insert adds the command path to the trie, the last definition wins
+func (n *commandNode) insert(cmd *CommandDef) {
+	for _, word := range cmd.Path {
+		child, ok := n.children[word]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[string]*commandNode)
+			}
+			child = &commandNode{}
+			n.children[word] = child
+			i, _ := slices.BinarySearch(n.words, word)
+			n.words = slices.Insert(n.words, i, word)
+		}
+		n = child
+	}
+	n.cmd = cmd
+}
+
+// commandIndex returns the flag index for cmd
+func (c *Compiled) commandIndex(cmd *CommandDef) *commandIndex {
+	if cmd != nil {
+		if idx, ok := c.commands[cmd]; ok {
+			return idx
+		}
+	}
+	return c.root
+}
+
+// findCommand searches for the longest matching command in the arguments.
+// Exact words win over prefixes when abbreviation is allowed.
+func (c *Compiled) findCommand(args []string) (*CommandDef, int, error) {
+	var (
+		bestMatch  *CommandDef
+		bestLength int
+		node       = c.trie
+	)
+
+	for j, arg := range args {
+		child, ok := node.children[arg]
+		if !ok && c.p.AllowAbbrev {
+			word, err := matchPrefix(arg, node.words)
+			if err != nil {
+				return nil, 0, err
+			}
+			child = node.children[word]
+		}
+		if child == nil {
+			break
+		}
+
+		node = child
+		if node.cmd != nil {
+			bestMatch = node.cmd
+			bestLength = j + 1
+		}
+	}
+
+	return bestMatch, bestLength, nil
+}
+
+// findFlag searches for a flag definition by name, global flags first
+func (c *Compiled) findFlag(name string, cmd *CommandDef) *Flag {
+	if f, ok := c.global[name]; ok {
+		return f
+	}
+	return c.commandIndex(cmd).flags[name]
+}
+
+// findFlagPrefix resolves a unique prefix of a long flag name or alias
+func (c *Compiled) findFlagPrefix(prefix string, cmd *CommandDef) (*Flag, error) {
+	var (
+		long      = c.commandIndex(cmd).long
+		matched   *Flag
+		ambiguous bool
+	)
+
+	i, _ := slices.BinarySearch(long, prefix)
+	j := i
+	for ; j < len(long) && strings.HasPrefix(long[j], prefix); j++ {
+		f := c.findFlag(long[j], cmd)
+		if matched != nil && matched != f {
+			ambiguous = true
+		}
+		matched = f
+	}
+
+	if ambiguous {
+		return nil, errorAmbiguous(prefix, slices.Clone(long[i:j]))
+	}
+	return matched, nil
+}
+
+// matchPrefix resolves a unique prefix among sorted words
+func matchPrefix(prefix string, words []string) (string, error) {
+	if prefix == "" {
+		return "", nil
+	}
+
+	i, _ := slices.BinarySearch(words, prefix)
+	j := i
+	for j < len(words) && strings.HasPrefix(words[j], prefix) {
+		j++
+	}
+
+	switch j - i {
+	case 0:
+		return "", nil
+	case 1:
+		return words[i], nil
+	}
+	return "", errorAmbiguous(prefix, slices.Clone(words[i:j]))
+}
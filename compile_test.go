@@ -0,0 +1,171 @@
+package paws
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[bool]("verbose", "v"))
+	parser.AddCommand([]string{"git", "commit"}, []*Flag{Paw[string]("message", "m"), Paw[bool]("amend")})
+	parser.AddCommand([]string{"git", "push"}, []*Flag{Paw[bool]("force", "f")})
+	c := parser.Compile()
+
+	result, err := c.Parse([]string{"git", "commit", "-v", "-m", "msg", "--amend", "file"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if result.Command == nil || result.Command.Path[1] != "commit" {
+		t.Fatalf("Command = %v, want commit", result.Command)
+	}
+	if !result.Bool("verbose") || result.String("message") != "msg" || !result.Bool("amend") {
+		t.Errorf("Flags = %v", result.Flags)
+	}
+
+	t.Run("command flags are scoped", func(t *testing.T) {
+		if _, err := c.Parse([]string{"git", "push", "--amend"}); !errors.Is(err, ErrUnknownFlag) {
+			t.Errorf("Parse() error = %v, want ErrUnknownFlag", err)
+		}
+	})
+
+	t.Run("snapshot ignores later registrations", func(t *testing.T) {
+		parser := New()
+		c := parser.Compile()
+		parser.AddFlags(Paw[bool]("late"))
+		if _, err := c.Parse([]string{"--late"}); !errors.Is(err, ErrUnknownFlag) {
+			t.Errorf("Parse() error = %v, want ErrUnknownFlag", err)
+		}
+	})
+
+	t.Run("longest path wins", func(t *testing.T) {
+		parser := New()
+		parser.AddCommand([]string{"git"}, nil)
+		parser.AddCommand([]string{"git", "remote", "add"}, nil)
+		result, err := parser.Compile().Parse([]string{"git", "remote", "x"})
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if result.Command == nil || len(result.Command.Path) != 1 {
+			t.Errorf("Command = %v, want [git]", result.Command)
+		}
+		if len(result.Positional) != 2 {
+			t.Errorf("Positional = %v, want [remote x]", result.Positional)
+		}
+	})
+}
+
+func TestCollisionPrecedence(t *testing.T) {
+	first, second := Paw[bool]("a"), Paw[bool]("b", "a")
+	parser := New()
+	parser.AddFlags(first, second)
+	cmdFirst, cmdSecond := Paw[bool]("c"), Paw[bool]("d", "c")
+	one := parser.AddCommand([]string{"run"}, []*Flag{cmdFirst, cmdSecond})
+	two := parser.AddCommand([]string{"run"}, nil)
+
+	c := parser.Compile()
+	if got := c.findFlag("a", nil); got != second {
+		t.Errorf("global collision resolved to %s, want the later flag b", got.Name)
+	}
+	if got := c.findFlag("c", one); got != cmdFirst {
+		t.Errorf("command collision resolved to %s, want the first flag c", got.Name)
+	}
+	if cmd, _, _ := c.findCommand([]string{"run"}); cmd != two {
+		t.Error("duplicate command should resolve to the later definition")
+	}
+}
+
+func TestParseCache(t *testing.T) {
+	parser := New()
+	cmd := parser.AddCommand([]string{"build"}, nil)
+	if _, err := parser.Parse([]string{"build"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	cached := parser.cache.compiled.Load()
+	if _, err := parser.Parse([]string{"build"}); err != nil || parser.cache.compiled.Load() != cached {
+		t.Fatalf("Parse() should reuse the compiled definitions, error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		edit func()
+		args []string
+	}{
+		{"AddFlags", func() { parser.AddFlags(Paw[bool]("verbose")) }, []string{"--verbose"}},
+		{"command flags", func() { cmd.Flags = append(cmd.Flags, Paw[bool]("force")) }, []string{"build", "--force"}},
+		{"settings", func() { parser.AllowAbbrev = true }, []string{"bu", "--verb"}},
+		{"AddCommand", func() { parser.AddCommand([]string{"test"}, []*Flag{Paw[int]("count")}) }, []string{"test", "--count=1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.edit()
+			if _, err := parser.Parse(tt.args); err != nil {
+				t.Errorf("Parse(%q) after edit error = %v", tt.args, err)
+			}
+		})
+	}
+}
+
+func TestCompiledConcurrent(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[bool]("verbose", "v"), Paw[int]("jobs", "j"))
+	parser.AddCommand([]string{"git", "commit"}, []*Flag{Paw[string]("message", "m")})
+	parser.AddCommand([]string{"git", "push"}, []*Flag{Paw[bool]("force", "f")})
+	c := parser.Compile()
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				args := []string{"git", "push", "-f", "--jobs", "4"}
+				if i%2 == 0 {
+					args = []string{"git", "commit", "-vm", "msg"}
+				}
+				result, err := c.Parse(args)
+				if err != nil {
+					t.Errorf("Parse() error = %v", err)
+					return
+				}
+				if err := c.ValidateRequired(result); err != nil {
+					t.Errorf("ValidateRequired() error = %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkParse(b *testing.B) {
+	parser := New()
+	parser.AddFlags(Paw[bool]("verbose", "v"), Paw[string]("config", "c"), Paw[int]("jobs", "j"))
+	parser.AddCommand([]string{"git", "commit"}, []*Flag{Paw[string]("message", "m"), Paw[bool]("amend")})
+	parser.AddCommand([]string{"git", "push"}, []*Flag{Paw[bool]("force", "f")})
+	args := []string{"git", "commit", "-v", "--message", "msg", "--jobs=4", "file"}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := parser.Parse(args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompiledParse(b *testing.B) {
+	parser := New()
+	parser.AddFlags(Paw[bool]("verbose", "v"), Paw[string]("config", "c"), Paw[int]("jobs", "j"))
+	parser.AddCommand([]string{"git", "commit"}, []*Flag{Paw[string]("message", "m"), Paw[bool]("amend")})
+	parser.AddCommand([]string{"git", "push"}, []*Flag{Paw[bool]("force", "f")})
+	c := parser.Compile()
+	args := []string{"git", "commit", "-v", "--message", "msg", "--jobs=4", "file"}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := c.Parse(args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
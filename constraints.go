@@ -0,0 +1,177 @@
+package paws
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Bound is one end of a numeric range
+type Bound struct {
+	Value     float64 // Limit value
+	Exclusive bool    // Whether the limit itself is excluded
+}
+
+// Choices restricts the flag to the given values, valid for string and numeric flags.
+// Numeric choices are compared by value, so "1.0" matches "1" for float flags.
+// Integer choices are read like values, as Go literals only when those are accepted.
+func (f *Flag) Choices(opts ...string) *Flag {
+	if f.Type != StringType && !f.isNumeric() {
+		panic("Choices can only be used on string/int/uint/float flags")
+	}
+
+	f.choices = make(map[string]struct{}, len(opts))
+	for _, o := range opts {
+		// Literals may be accepted later, by the flag or the parser
+		if _, err := f.choiceKey(o, 10); err != nil {
+			if _, err := f.choiceKey(o, 0); err != nil {
+				panic(fmt.Sprintf("invalid choice %q for %s flag %s", o, f.Type, f.Name))
+			}
+		}
+		f.choices[o] = struct{}{}
+	}
+	f.ChoicesOpt = opts
+	return f
+}
+
+// Range sets an inclusive range, only valid for numeric flags.
+func (f *Flag) Range(min, max float64) *Flag {
+	return f.AtLeast(min).AtMost(max)
+}
+
+// AtLeast sets an inclusive lower bound, only valid for numeric flags.
+func (f *Flag) AtLeast(v float64) *Flag {
+	f.mustBeNumeric("AtLeast")
+	f.Min = &Bound{Value: v}
+	return f
+}
+
+// AtMost sets an inclusive upper bound, only valid for numeric flags.
+func (f *Flag) AtMost(v float64) *Flag {
+	f.mustBeNumeric("AtMost")
+	f.Max = &Bound{Value: v}
+	return f
+}
+
+// Above sets an exclusive lower bound, only valid for numeric flags.
+func (f *Flag) Above(v float64) *Flag {
+	f.mustBeNumeric("Above")
+	f.Min = &Bound{Value: v, Exclusive: true}
+	return f
+}
+
+// Below sets an exclusive upper bound, only valid for numeric flags.
+func (f *Flag) Below(v float64) *Flag {
+	f.mustBeNumeric("Below")
+	f.Max = &Bound{Value: v, Exclusive: true}
+	return f
+}
+
+func (f *Flag) mustBeNumeric(method string) {
+	if !f.isNumeric() {
+		panic(method + " can only be used on int/uint/float flags")
+	}
+}
+
+// isNumeric reports whether the flag holds a number
+func (f *Flag) isNumeric() bool {
+	return f.Type == FloatType || f.Type.isSigned() || f.Type.isUnsigned()
+}
+
+// choiceKey returns the canonical form of a choice, integers are parsed in base
+func (f *Flag) choiceKey(v string, base int) (string, error) {
+	switch {
+	case f.Type.isSigned():
+		i, err := strconv.ParseInt(v, base, f.Type.bitSize())
+		return strconv.FormatInt(i, 10), err
+	case f.Type.isUnsigned():
+		u, err := strconv.ParseUint(v, base, f.Type.bitSize())
+		return strconv.FormatUint(u, 10), err
+	case f.Type == FloatType:
+		x, err := strconv.ParseFloat(v, 64)
+		return strconv.FormatFloat(x, 'g', -1, 64), err
+	}
+	return v, nil
+}
+
+// checkNumber validates a parsed number against the range and choices,
+// key is the canonical form of the number and base the one of integer choices
+func (f *Flag) checkNumber(x float64, key string, base int) error {
+	if len(f.choices) > 0 && !f.hasChoice(key, base) {
+		return fmt.Errorf("value %s not in allowed choices: %v", shownValue(f, key), f.ChoicesOpt)
+	}
+	if !f.inRange(x) {
+		return fmt.Errorf("value %s out of range %s", shownValue(f, key), f.rangeString())
+	}
+	return nil
+}
+
+// hasChoice reports whether a numeric choice has the canonical form key
+func (f *Flag) hasChoice(key string, base int) bool {
+	for _, o := range f.ChoicesOpt {
+		if k, err := f.choiceKey(o, base); err == nil && k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// inRange reports whether x satisfies both bounds, NaN satisfies none
+func (f *Flag) inRange(x float64) bool {
+	if math.IsNaN(x) {
+		return f.Min == nil && f.Max == nil
+	}
+	if b := f.Min; b != nil && (x < b.Value || (b.Exclusive && x == b.Value)) {
+		return false
+	}
+	if b := f.Max; b != nil && (x > b.Value || (b.Exclusive && x == b.Value)) {
+		return false
+	}
+	return true
+}
+
+// emptyRange reports whether no value can satisfy both bounds
+func (f *Flag) emptyRange() bool {
+	if f.Min == nil || f.Max == nil {
+		return false
+	}
+	if f.Min.Exclusive || f.Max.Exclusive {
+		return f.Min.Value >= f.Max.Value
+	}
+	return f.Min.Value > f.Max.Value
+}
+
+// rangeString formats the range, e.g. "[0, 1]", "(0, 2.5)" or ">= 1"
+func (f *Flag) rangeString() string {
+	num := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+
+	switch {
+	case f.Min == nil && f.Max == nil:
+		return ""
+	case f.Max == nil:
+		if f.Min.Exclusive {
+			return "> " + num(f.Min.Value)
+		}
+		return ">= " + num(f.Min.Value)
+	case f.Min == nil:
+		if f.Max.Exclusive {
+			return "< " + num(f.Max.Value)
+		}
+		return "<= " + num(f.Max.Value)
+	}
+
+	var b strings.Builder
+	if f.Min.Exclusive {
+		b.WriteByte('(')
+	} else {
+		b.WriteByte('[')
+	}
+	b.WriteString(num(f.Min.Value) + ", " + num(f.Max.Value))
+	if f.Max.Exclusive {
+		b.WriteByte(')')
+	} else {
+		b.WriteByte(']')
+	}
+	return b.String()
+}
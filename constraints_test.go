@@ -0,0 +1,230 @@
+package paws
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNumericConstraints(t *testing.T) {
+	parser := New()
+
+	tests := []struct {
+		name    string
+		flag    *Flag
+		value   string
+		wantErr string
+	}{
+		{"float in range", Paw[float64]("ratio").Range(0.5, 2.5), "2.5", ""},
+		{"float below range", Paw[float64]("ratio").Range(0.5, 2.5), "0.4", "out of range [0.5, 2.5]"},
+		{"NaN in range", Paw[float64]("ratio").Range(0, 1), "NaN", "out of range [0, 1]"},
+		{"NaN below max", Paw[float64]("ratio").Below(1), "nan", "out of range < 1"},
+		{"NaN without range", Paw[float64]("ratio"), "NaN", ""},
+		{"exactly zero", Paw[int]("n").Range(0, 0), "0", ""},
+		{"exactly zero rejects one", Paw[int]("n").Range(0, 0), "1", "out of range [0, 0]"},
+		{"exclusive lower", Paw[float64]("ratio").Above(0), "0", "out of range > 0"},
+		{"exclusive upper", Paw[float64]("ratio").Below(1), "0.999", ""},
+		{"min only", Paw[int]("jobs").AtLeast(1), "1000", ""},
+		{"min only rejects", Paw[int]("jobs").AtLeast(1), "0", "out of range >= 1"},
+		{"max only", Paw[uint]("size").AtMost(10), "11", "out of range <= 10"},
+		{"negative int range", Paw[int]("offset").Range(-10, -1), "-5", ""},
+		{"int choice", Paw[int]("level").Choices("1", "3", "5"), "3", ""},
+		{"int choice rejects", Paw[int]("level").Choices("1", "3", "5"), "2", "not in allowed choices: [1 3 5]"},
+		{"float choice by value", Paw[float64]("scale").Choices("0.5", "1", "2"), "1.0", ""},
+		{"uint choice", Paw[uint]("bits").Choices("8", "16"), "32", "not in allowed choices"},
+		{"decimal choice", Paw[int]("mode").Choices("010"), "10", ""},
+		{"decimal choice is not octal", Paw[int]("mode").Choices("010"), "8", "not in allowed choices"},
+		{"literal choice", Paw[int]("mode").Choices("010", "0x10").AcceptLiterals(), "8", ""},
+		{"literal choice by value", Paw[int]("mode").Choices("010", "0x10").AcceptLiterals(), "0o20", ""},
+		{"literal choice rejects", Paw[int]("mode").Choices("010").AcceptLiterals(), "10", "not in allowed choices"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parser.validateFlagValue(tt.flag, tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateFlagValue() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateFlagValue() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConstraintsInHelpAndLint(t *testing.T) {
+	parser := New()
+	parser.AddFlags(
+		Paw[float64]("ratio").Above(0).AtMost(1).Help("mix ratio"),
+		Paw[int]("level").Choices("1", "2").Default(3),
+		Paw[int]("empty").Above(1).Below(1),
+	)
+
+	var b strings.Builder
+	if err := parser.WriteHelp(&b, nil); err != nil {
+		t.Fatalf("WriteHelp() error = %v", err)
+	}
+	if !strings.Contains(b.String(), "mix ratio (range: (0, 1])") {
+		t.Errorf("help missing range:\n%s", b.String())
+	}
+
+	codes := make(map[LintCode]bool)
+	for _, d := range parser.Lint() {
+		codes[d.Code] = true
+	}
+	if !codes[LintDefaultChoice] || !codes[LintInvalidRange] {
+		t.Errorf("Lint() = %v, want default-choice and invalid-range", parser.Lint())
+	}
+}
+
+func TestIntLiterals(t *testing.T) {
+	parser := New()
+	parser.AddFlags(
+		Paw[int]("mask").AcceptLiterals(),
+		Paw[uint]("mode").AcceptLiterals(),
+		Paw[int]("size").AcceptLiterals(),
+		Paw[int]("plain"),
+		Paw[int]("offset").AcceptLiterals(),
+	)
+
+	result, err := parser.Parse([]string{"--mask", "0xff", "--mode", "0755", "--size", "1_000_000", "--offset", "-0x10"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := map[string]int{"mask": 255, "size": 1000000, "offset": -16}
+	for name, v := range want {
+		if got := result.Int(name); got != v {
+			t.Errorf("%s = %d, want %d", name, got, v)
+		}
+	}
+	if got := result.Uint("mode"); got != 0o755 {
+		t.Errorf("mode = %o, want 755", got)
+	}
+
+	if _, err := parser.Parse([]string{"--plain", "0xff"}); !errors.Is(err, ErrFlagValue) {
+		t.Errorf("Parse() error = %v, want ErrFlagValue without literals", err)
+	}
+
+	t.Run("parser wide", func(t *testing.T) {
+		parser := New()
+		parser.IntLiterals = true
+		parser.AddFlags(Paw[int64]("id"))
+		result, err := parser.Parse([]string{"--id=0b1010"})
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if v, err := Get[int64](result, "id"); err != nil || v != 10 {
+			t.Errorf("Get[int64](id) = %d, %v, want 10", v, err)
+		}
+	})
+}
+
+func TestFixedWidthIntegers(t *testing.T) {
+	parser := New()
+	parser.AddFlags(
+		Paw[int8]("i8"),
+		Paw[uint8]("u8").Default(7),
+		Paw[int16]("i16").Default(-300),
+		Paw[uint32]("u32"),
+		Paw[int64]("i64").Range(-5, 5),
+		Paw[uint64]("u64"),
+	)
+
+	tests := []struct {
+		name     string
+		args     []string
+		overflow bool
+		wantErr  bool
+	}{
+		{"int8 max", []string{"--i8", "127"}, false, false},
+		{"int8 overflow", []string{"--i8", "128"}, true, true},
+		{"int8 negative overflow", []string{"--i8", "-129"}, true, true},
+		{"uint8 overflow", []string{"--u8", "256"}, true, true},
+		{"uint8 negative", []string{"--u8", "-1"}, false, true},
+		{"int16 overflow", []string{"--i16", "40000"}, true, true},
+		{"uint32 max", []string{"--u32", "4294967295"}, false, false},
+		{"int64 range", []string{"--i64", "6"}, false, true},
+		{"uint64 max", []string{"--u64", "18446744073709551615"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrOverflow) != tt.overflow {
+				t.Errorf("Parse() error = %v, overflow %v", err, tt.overflow)
+			}
+		})
+	}
+
+	result, err := parser.Parse([]string{"--i8", "-3", "--u64", "42"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if v := MustGet[int8](result, "i8"); v != -3 {
+		t.Errorf("i8 = %d, want -3", v)
+	}
+	if v := MustGet[uint8](result, "u8"); v != 7 {
+		t.Errorf("u8 = %d, want default 7", v)
+	}
+	if v := MustGet[uint64](result, "u64"); v != 42 {
+		t.Errorf("u64 = %d, want 42", v)
+	}
+	_, err = parser.Parse([]string{"--i8", "128"})
+	var pe *ParseError
+	if !errors.As(err, &pe) || errors.Unwrap(err) != ErrFlagValue || !errors.Is(err, ErrOverflow) {
+		t.Errorf("Parse() error = %v, want ParseError unwrapping to ErrFlagValue with an overflow cause", err)
+	}
+	var numErr *strconv.NumError
+	wrapped := &ParseError{Err: ErrFlagValue, Flag: "n", Cause: &strconv.NumError{Func: "ParseInt", Num: "x", Err: strconv.ErrSyntax}}
+	if !errors.As(wrapped, &numErr) || numErr.Num != "x" || !errors.Is(wrapped, strconv.ErrSyntax) {
+		t.Errorf("errors.As(NumError) = %v, want the cause", numErr)
+	}
+
+	if v := MustGet[int16](result, "i16"); v != -300 {
+		t.Errorf("i16 = %d, want default -300", v)
+	}
+	if err := parser.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	t.Run("default conversion", func(t *testing.T) {
+		if d := Paw[uint32]("n").Default(uint(9)).DefValue; d != uint32(9) {
+			t.Errorf("DefValue = %#v, want uint32(9)", d)
+		}
+		if d := Paw[float64]("x").Default(2).DefValue; d != 2.0 {
+			t.Errorf("DefValue = %#v, want float64(2)", d)
+		}
+		// Defaults set directly are accepted when they fit
+		f := Paw[int8]("lvl")
+		f.DefValue = 5
+		if v, ok := defaultValue(f); !ok || v != "5" {
+			t.Errorf("defaultValue() = %q, %v, want 5", v, ok)
+		}
+	})
+
+	for _, tt := range []struct {
+		name string
+		flag func() *Flag
+	}{
+		{"overflow", func() *Flag { return Paw[uint8]("u8").Default(256) }},
+		{"negative unsigned", func() *Flag { return Paw[uint16]("u16").Default(-1) }},
+		{"fraction", func() *Flag { return Paw[int]("n").Default(1.5) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Default() should panic")
+				}
+			}()
+			tt.flag()
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package paws
+
+import (
+	"slices"
+	"strings"
+)
+
+// commandWords returns the program name followed by the command path
+func commandWords(name string, cmd *CommandDef) []string {
+	var words []string
+	if name != "" {
+		words = append(words, name)
+	}
+	if cmd != nil {
+		words = append(words, cmd.Path...)
+	}
+	return words
+}
+
+// requiredFlags returns the required flags of cmd and the global flags
+func (p *Parser) requiredFlags(cmd *CommandDef) []*Flag {
+	var required []*Flag
+	for _, f := range p.scopeFlags(cmd) {
+		if f.IsRequired {
+			required = append(required, f)
+		}
+	}
+	return required
+}
+
+// scopeFlags returns the command flags followed by the global flags
+func (p *Parser) scopeFlags(cmd *CommandDef) []*Flag {
+	if cmd == nil {
+		return p.Flags
+	}
+	return append(slices.Clip(cmd.Flags), p.Flags...)
+}
+
+// parentOf returns the longest registered command whose path is a proper prefix of cmd's path
+func (p *Parser) parentOf(cmd *CommandDef) *CommandDef {
+	if cmd == nil {
+		return nil
+	}
+
+	var parent *CommandDef
+	for _, c := range p.Commands {
+		if len(c.Path) < len(cmd.Path) && slices.Equal(c.Path, cmd.Path[:len(c.Path)]) {
+			if parent == nil || len(c.Path) > len(parent.Path) {
+				parent = c
+			}
+		}
+	}
+	return parent
+}
+
+// childrenOf returns the commands whose parent is cmd, or the top level commands for nil
+func (p *Parser) childrenOf(cmd *CommandDef) []*CommandDef {
+	var children []*CommandDef
+	for _, c := range p.Commands {
+		if c != cmd && p.parentOf(c) == cmd && (cmd != nil || len(c.Path) > 0) {
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+// siblingsOf returns the commands sharing the parent of cmd
+func (p *Parser) siblingsOf(cmd *CommandDef) []*CommandDef {
+	if cmd == nil {
+		return nil
+	}
+	var siblings []*CommandDef
+	for _, c := range p.childrenOf(p.parentOf(cmd)) {
+		if c != cmd {
+			siblings = append(siblings, c)
+		}
+	}
+	return siblings
+}
+
+// summary returns the first line of a description
+func summary(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return line
+}
+
+// usageLine returns a plain text synopsis, e.g. "tool add [options] --url VALUE <name>"
+func (p *Parser) usageLine(name string, cmd *CommandDef) string {
+	words := commandWords(name, cmd)
+
+	required := p.requiredFlags(cmd)
+	if len(p.scopeFlags(cmd)) > len(required) {
+		words = append(words, "[options]")
+	}
+	for _, f := range required {
+		words = append(words, f.synopsis())
+	}
+
+	if cmd == nil {
+		if len(p.childrenOf(nil)) > 0 {
+			words = append(words, "<command>")
+		}
+		return strings.Join(words, " ")
+	}
+	for _, a := range cmd.Positionals {
+		words = append(words, a.Usage())
+	}
+	return strings.Join(words, " ")
+}
+
+// synopsis returns the flag by its name with its placeholder, e.g. "--url VALUE"
+func (f *Flag) synopsis() string {
+	if f.Type == BoolType {
+		return dashed(f.Name)
+	}
+	return dashed(f.Name) + " " + f.placeholder()
+}
@@ -0,0 +1,135 @@
+package paws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnumValue maps a command line token to a value of a Go type
+type EnumValue[T any] struct {
+	Token   string   // Token as written on the command line
+	Aliases []string // Alternative tokens
+	Value   T        // Go value the token stands for
+	Desc    string   // Description shown in help and completion
+}
+
+// EnumOption describes one token of an enum flag, without its Go value
+type EnumOption struct {
+	Token   string   `json:"token"`             // Token as written on the command line
+	Aliases []string `json:"aliases,omitempty"` // Alternative tokens
+	Desc    string   `json:"desc,omitempty"`    // Description shown in help and completion
+}
+
+// Label returns the token followed by its aliases, e.g. "fast (f, quick)"
+func (o EnumOption) Label() string {
+	if len(o.Aliases) == 0 {
+		return o.Token
+	}
+	return o.Token + " (" + strings.Join(o.Aliases, ", ") + ")"
+}
+
+// Enum creates a flag accepting the given tokens, read back as T with GetEnum.
+// A default is set with Default and the token, e.g. Default("fast").
+func Enum[T any](name string, values []EnumValue[T], aliases ...string) *Flag {
+	f := &Flag{
+		Name:       name,
+		Aliases:    aliases,
+		Type:       EnumType,
+		DefValue:   "",
+		enumIndex:  make(map[string]string),
+		enumValues: make(map[string]any, len(values)),
+	}
+
+	for _, v := range values {
+		f.EnumOpts = append(f.EnumOpts, EnumOption{Token: v.Token, Aliases: v.Aliases, Desc: v.Desc})
+		f.ChoicesOpt = append(f.ChoicesOpt, v.Token)
+		f.enumValues[v.Token] = v.Value
+		for _, t := range append([]string{v.Token}, v.Aliases...) {
+			if _, ok := f.enumIndex[t]; ok {
+				panic(fmt.Sprintf("duplicate token %q for enum flag %s", t, name))
+			}
+			f.enumIndex[t] = v.Token
+		}
+	}
+	return f
+}
+
+// IgnoreCase makes enum tokens match regardless of case
+func (f *Flag) IgnoreCase() *Flag {
+	if f.Type != EnumType {
+		panic("IgnoreCase can only be used on enum flags")
+	}
+	f.FoldCase = true
+	return f
+}
+
+// GetEnum returns the Go value of an enum flag, using the default when not given
+func GetEnum[T any](r *ParseResult, name string) (T, error) {
+	var zero T
+
+	f := r.findFlag(name)
+	if f == nil {
+		return zero, errorUnknownFlag(name)
+	}
+	if f.Type != EnumType {
+		return zero, &ParseError{Err: ErrTypeMismatch, Flag: f.Name, Cause: fmt.Errorf("flag is %s, not enum", f.Type)}
+	}
+
+	token := r.String(f.Name)
+	if token == "" {
+		return zero, nil
+	}
+
+	canonical, ok := f.enumToken(token)
+	if !ok {
+		return zero, errorFlagValue(f, token, f.enumError(token))
+	}
+
+	v, ok := f.enumValues[canonical].(T)
+	if !ok {
+		return zero, &ParseError{
+			Err:   ErrTypeMismatch,
+			Flag:  f.Name,
+			Cause: fmt.Errorf("enum holds %T, not %T", f.enumValues[canonical], zero),
+		}
+	}
+	return v, nil
+}
+
+// MustGetEnum is like GetEnum but panics on error
+func MustGetEnum[T any](r *ParseResult, name string) T {
+	v, err := GetEnum[T](r, name)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// enumToken resolves a token or alias to its canonical token. Ignoring
+// case, the first match in declaration order wins, tokens before aliases.
+func (f *Flag) enumToken(v string) (string, bool) {
+	if t, ok := f.enumIndex[v]; ok {
+		return t, true
+	}
+	if !f.FoldCase {
+		return "", false
+	}
+	for _, o := range f.EnumOpts {
+		if strings.EqualFold(o.Token, v) {
+			return o.Token, true
+		}
+	}
+	for _, o := range f.EnumOpts {
+		for _, a := range o.Aliases {
+			if strings.EqualFold(a, v) {
+				return o.Token, true
+			}
+		}
+	}
+	return "", false
+}
+
+// enumError describes an invalid enum token
+func (f *Flag) enumError(v string) error {
+	return fmt.Errorf("value '%s' not in allowed choices: %v", shownValue(f, v), f.ChoicesOpt)
+}
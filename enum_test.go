@@ -0,0 +1,118 @@
+package paws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type speed int
+
+const (
+	speedNormal speed = iota
+	speedFast
+	speedSlow
+)
+
+func TestEnum(t *testing.T) {
+	values := []EnumValue[speed]{
+		{Token: "normal", Value: speedNormal, Desc: "balanced"},
+		{Token: "fast", Aliases: []string{"f", "quick"}, Value: speedFast, Desc: "optimise for speed"},
+		{Token: "slow", Value: speedSlow, Desc: "optimise for size"},
+	}
+	parser := New()
+	parser.AddFlags(Enum("mode", values, "m").Default("normal"))
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    speed
+		token   string
+		wantErr bool
+	}{
+		{"default", nil, speedNormal, "normal", false},
+		{"token", []string{"--mode", "fast"}, speedFast, "fast", false},
+		{"alias", []string{"-m", "quick"}, speedFast, "fast", false},
+		{"short alias", []string{"--mode=f"}, speedFast, "fast", false},
+		{"case sensitive", []string{"--mode", "FAST"}, 0, "", true},
+		{"unknown", []string{"--mode", "turbo"}, 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got, err := GetEnum[speed](result, "mode"); err != nil || got != tt.want {
+				t.Errorf("GetEnum() = %v, %v, want %v", got, err, tt.want)
+			}
+			if got := result.String("mode"); got != tt.token {
+				t.Errorf("String() = %q, want %q", got, tt.token)
+			}
+		})
+	}
+
+	t.Run("ignore case", func(t *testing.T) {
+		parser := New()
+		parser.AddFlags(Enum("mode", values, "m").IgnoreCase())
+		result, err := parser.Parse([]string{"--mode", "Quick"})
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if got := MustGetEnum[speed](result, "mode"); got != speedFast {
+			t.Errorf("mode = %v, want fast", got)
+		}
+	})
+
+	t.Run("ignore case collision", func(t *testing.T) {
+		f := Enum("level", []EnumValue[int]{
+			{Token: "low", Aliases: []string{"Hi"}},
+			{Token: "LOW"},
+			{Token: "high", Aliases: []string{"hi"}},
+		}).IgnoreCase()
+		// The first declared token wins, and tokens win over aliases
+		for range 20 {
+			if got, _ := f.enumToken("Low"); got != "low" {
+				t.Fatalf("enumToken(Low) = %q, want low", got)
+			}
+			if got, _ := f.enumToken("HI"); got != "low" {
+				t.Fatalf("enumToken(HI) = %q, want low", got)
+			}
+		}
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		result, _ := parser.Parse(nil)
+		if _, err := GetEnum[string](result, "mode"); !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("GetEnum[string]() error = %v, want ErrTypeMismatch", err)
+		}
+	})
+}
+
+func TestEnumHelpAndLint(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Enum("mode", []EnumValue[speed]{
+		{Token: "normal", Value: speedNormal, Desc: "balanced"},
+		{Token: "fast", Aliases: []string{"f", "quick"}, Value: speedFast, Desc: "optimise for speed"},
+		{Token: "slow", Value: speedSlow, Desc: "optimise for size"},
+	}, "m").Help("build mode").Default("turbo"))
+
+	var b strings.Builder
+	if err := parser.WriteHelp(&b, nil); err != nil {
+		t.Fatalf("WriteHelp() error = %v", err)
+	}
+	for _, want := range []string{"build mode (one of: normal, fast, slow", "fast (f, quick)", "optimise for speed"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("help missing %q:\n%s", want, b.String())
+		}
+	}
+
+	diags := parser.Lint()
+	if len(diags) != 1 || diags[0].Code != LintDefaultChoice {
+		t.Errorf("Lint() = %v, want one default-choice", diags)
+	}
+}
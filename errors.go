@@ -3,6 +3,7 @@ package paws
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Common error types for argument parsing
@@ -12,14 +13,22 @@ var (
 	ErrMissingValue = errors.New("flag requires value")
 	ErrRequiredFlag = errors.New("required flag missing")
 	ErrParse        = errors.New("parse error")
+	ErrAmbiguous    = errors.New("ambiguous abbreviation")
+	ErrDefinition   = errors.New("invalid definition")
+	ErrTypeMismatch = errors.New("flag type mismatch")
+	ErrOverflow     = errors.New("value out of range for type")
+	ErrStaleDocs    = errors.New("generated docs out of date")
 )
 
 // ParseError represents a parsing error with context
 type ParseError struct {
-	Err   error  // Error type (ErrUnknownFlag, etc)
-	Flag  string // Flag name involved
-	Value string // Flag value if any
-	Cause error  // Underlying error
+	Err    error  // Error type (ErrUnknownFlag, etc)
+	Flag   string // Flag name involved
+	Value  string // Flag value if any
+	Cause  error  // Underlying error
+	Secret bool   // Whether Value is sensitive, it is then left out of the message
+
+	Candidates []string // Possible matches for an ambiguous abbreviation
 }
 
 // Error returns a formatted error message
@@ -27,6 +36,12 @@ func (e *ParseError) Error() string {
 	if e.Cause != nil {
 		return fmt.Sprintf("%s: %s (%s)", e.Err.Error(), e.Flag, e.Cause.Error())
 	}
+	if e.Secret {
+		return fmt.Sprintf("%s: %s (value %s)", e.Err.Error(), e.Flag, redacted)
+	}
+	if len(e.Candidates) > 0 {
+		return fmt.Sprintf("%s: %s (candidates: %s)", e.Err.Error(), e.Flag, strings.Join(e.Candidates, ", "))
+	}
 	if e.Value != "" {
 		return fmt.Sprintf("%s: %s with value %s", e.Err.Error(), e.Flag, e.Value)
 	}
@@ -36,6 +51,68 @@ func (e *ParseError) Error() string {
 // Unwrap returns the underlying error
 func (e *ParseError) Unwrap() error { return e.Err }
 
+// Is reports whether the cause matches target, errors.Is checks Err through Unwrap
+func (e *ParseError) Is(target error) bool {
+	return e.Cause != nil && errors.Is(e.Cause, target)
+}
+
+// As finds the first error in the cause matching target, errors.As checks Err through Unwrap
+func (e *ParseError) As(target any) bool {
+	return e.Cause != nil && errors.As(e.Cause, target)
+}
+
+// DefinitionError holds all problems found in flag and command definitions
+type DefinitionError struct {
+	Diagnostics []Diagnostic
+}
+
+// Error returns all diagnostics, one per line
+func (e *DefinitionError) Error() string {
+	lines := make([]string, 0, len(e.Diagnostics)+1)
+	lines = append(lines, fmt.Sprintf("%s: %d problem(s)", ErrDefinition.Error(), len(e.Diagnostics)))
+	for _, d := range e.Diagnostics {
+		lines = append(lines, "  "+d.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns ErrDefinition
+func (e *DefinitionError) Unwrap() error { return ErrDefinition }
+
+// SpecError reports a problem in a JSON spec along with its location
+type SpecError struct {
+	Path string // JSON path of the offending value, e.g. "$.commands[2].flags[0].type"
+	Err  error  // Underlying error
+}
+
+// Error returns the path followed by the problem
+func (e *SpecError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns ErrDefinition and the underlying error
+func (e *SpecError) Unwrap() []error { return []error{ErrDefinition, e.Err} }
+
+// SyntaxError reports a malformed usage pattern or help text
+type SyntaxError struct {
+	Text   string // Offending line
+	Line   int    // Line number in the help text, 0 for a single pattern
+	Column int    // Column of the problem, starting at 1
+	Msg    string // Description of the problem
+}
+
+// Error returns the problem followed by the line and a caret under the column
+func (e *SyntaxError) Error() string {
+	where := fmt.Sprintf("column %d", e.Column)
+	if e.Line > 0 {
+		where = fmt.Sprintf("line %d, %s", e.Line, where)
+	}
+	return fmt.Sprintf("%s: %s at %s\n  %s\n  %s^", ErrDefinition.Error(), e.Msg, where, e.Text, strings.Repeat(" ", e.Column-1))
+}
+
+// Unwrap returns ErrDefinition
+func (e *SyntaxError) Unwrap() error { return ErrDefinition }
+
 // helpers to build typed ParseError
 func errorUnknownFlag(flag string) *ParseError {
 	return &ParseError{Err: ErrUnknownFlag, Flag: flag}
@@ -48,3 +125,11 @@ func errorMissingValue(flag string) *ParseError {
 func errorRequiredFlag(flag string) *ParseError {
 	return &ParseError{Err: ErrRequiredFlag, Flag: flag}
 }
+
+func errorFlagValue(f *Flag, value string, cause error) *ParseError {
+	return &ParseError{Err: ErrFlagValue, Flag: f.Name, Value: value, Cause: cause, Secret: f.IsSecret}
+}
+
+func errorAmbiguous(name string, candidates []string) *ParseError {
+	return &ParseError{Err: ErrAmbiguous, Flag: name, Candidates: candidates}
+}
@@ -1,5 +1,11 @@
 package paws
 
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
 type FlagType int
 
 const (
@@ -8,62 +14,197 @@ const (
 	IntType                    // Integer flag
 	UintType                   // Unsigned integer flag
 	FloatType                  // Floating point flag
+	PathType                   // Filesystem path flag
+	FileType                   // File handle flag, opened lazily
+	Int8Type                   // 8-bit integer flag
+	Int16Type                  // 16-bit integer flag
+	Int32Type                  // 32-bit integer flag
+	Int64Type                  // 64-bit integer flag
+	Uint8Type                  // 8-bit unsigned integer flag
+	Uint16Type                 // 16-bit unsigned integer flag
+	Uint32Type                 // 32-bit unsigned integer flag
+	Uint64Type                 // 64-bit unsigned integer flag
+	EnumType                   // Token mapped to a Go value
 )
 
+// basicTypes maps flag types to the predeclared Go type holding their values
+var basicTypes = map[FlagType]reflect.Type{
+	BoolType:   reflect.TypeFor[bool](),
+	StringType: reflect.TypeFor[string](),
+	IntType:    reflect.TypeFor[int](),
+	UintType:   reflect.TypeFor[uint](),
+	FloatType:  reflect.TypeFor[float64](),
+	Int8Type:   reflect.TypeFor[int8](),
+	Int16Type:  reflect.TypeFor[int16](),
+	Int32Type:  reflect.TypeFor[int32](),
+	Int64Type:  reflect.TypeFor[int64](),
+	Uint8Type:  reflect.TypeFor[uint8](),
+	Uint16Type: reflect.TypeFor[uint16](),
+	Uint32Type: reflect.TypeFor[uint32](),
+	Uint64Type: reflect.TypeFor[uint64](),
+}
+
+// typeOfKind returns the flag type for values of the given Go kind
+func typeOfKind(k reflect.Kind) (FlagType, bool) {
+	for t, rt := range basicTypes {
+		if rt.Kind() == k {
+			return t, true
+		}
+	}
+	return BoolType, false
+}
+
+// String returns the type name used in help and errors
+func (t FlagType) String() string {
+	switch t {
+	case StringType:
+		return "string"
+	case FloatType:
+		return "float"
+	case PathType:
+		return "path"
+	case FileType:
+		return "file"
+	case EnumType:
+		return "enum"
+	}
+	if rt, ok := basicTypes[t]; ok {
+		return rt.String()
+	}
+	return "unknown"
+}
+
+// isSigned reports whether t is a signed integer type
+func (t FlagType) isSigned() bool {
+	return t == IntType || (t >= Int8Type && t <= Int64Type)
+}
+
+// isUnsigned reports whether t is an unsigned integer type
+func (t FlagType) isUnsigned() bool {
+	return t == UintType || (t >= Uint8Type && t <= Uint64Type)
+}
+
+// bitSize returns the integer size in bits, 0 for int and uint
+func (t FlagType) bitSize() int {
+	switch t {
+	case Int8Type, Uint8Type:
+		return 8
+	case Int16Type, Uint16Type:
+		return 16
+	case Int32Type, Uint32Type:
+		return 32
+	case Int64Type, Uint64Type:
+		return 64
+	}
+	return 0
+}
+
 // FlagTypeConstraint defines the allowed types for flag values
 type FlagTypeConstraint interface {
-	~string | ~bool | ~int | ~uint | ~float64
+	~string | ~bool | ~int | ~uint | ~float64 |
+		~int8 | ~int16 | ~int32 | ~int64 |
+		~uint8 | ~uint16 | ~uint32 | ~uint64
 }
 
 // Flag represents a command line flag definition
 type Flag struct {
-	Name       string   // Flag name (long form)
-	Aliases    []string // Short aliases (single characters)
-	Type       FlagType // Flag's type (string, bool, etc)
-	DefValue   any      // Default value
-	IsRequired bool     // Whether the flag is required
-	ChoicesOpt []string // Allowed values for string flags
-	Min, Max   int      // Range constraints for integer flags
-	HelpText   string   // Help description
+	Name       string    // Flag name (long form)
+	Aliases    []string  // Short aliases (single characters)
+	Type       FlagType  // Flag's type (string, bool, etc)
+	DefValue   any       // Default value
+	IsRequired bool      // Whether the flag is required
+	ChoicesOpt []string  // Allowed values for string flags
+	Min, Max   *Bound    // Range constraints for numeric flags, nil when unbounded
+	HelpText   string    // Help description
+	MetaVar    string    // Value placeholder shown in help
+	PathCheck  PathCheck // Filesystem constraints for path flags
+	AbsPath    bool      // Whether path values are made absolute
+	FileMode   FileMode  // Open mode for file flags
 
-	choices map[string]struct{}
+	IsOptional   bool         // Whether the value may be omitted
+	NoOptDefault string       // Implicit value when used without "=value"
+	AllowHyphen  bool         // Whether values may start with "-"
+	EnvVars      []string     // Environment variables read when the flag is not given
+	IntLiterals  bool         // Accept Go integer literals such as 0xff, 0o755 and 1_000
+	EnumOpts     []EnumOption // Tokens of enum flags
+	FoldCase     bool         // Whether enum tokens ignore case
+	IsSecret     bool         // Whether the value is sensitive, hidden from prompts, help and errors
+	FileFlag     bool         // Whether --<name>-file reads the value from a file, "-" for stdin
+
+	hasDefault bool // Whether DefValue was set, rather than left at the zero value of Paw
+	choices    map[string]struct{}
+	enumIndex  map[string]string // Enum tokens and aliases to canonical token
+	enumValues map[string]any    // Canonical enum token to Go value
+	bind       func(*ParseResult) error
+	fileOf     *Flag // Flag whose value this companion reads from a file
 }
 
 // Paw creates a new flag with the specified name and aliases
 // The type is automatically determined from the generic type parameter T
 func Paw[T FlagTypeConstraint](name string, aliases ...string) *Flag {
-	var (
-		def T
-		t   FlagType
-	)
-
-	switch any(def).(type) {
-	case bool:
-		t = BoolType
-	case int:
-		t = IntType
-	case uint:
-		t = UintType
-	case float64:
-		t = FloatType
-	case string:
-		t = StringType
-	}
-
+	var def T
 	return &Flag{
 		Name:     name,
 		Aliases:  aliases,
-		Type:     t,
-		DefValue: def,
+		Type:     flagTypeOf[T](),
+		DefValue: baseValue(def),
 	}
 }
 
-// Default sets the default value for the flag
+// flagTypeOf returns the flag type for T based on its underlying kind
+func flagTypeOf[T FlagTypeConstraint]() FlagType {
+	t, _ := typeOfKind(reflect.TypeFor[T]().Kind())
+	return t
+}
+
+// baseValue converts a value of a named type to its underlying type,
+// so defaults of e.g. "type Port int" are stored as int
+func baseValue[T FlagTypeConstraint](v T) any {
+	rv := reflect.ValueOf(v)
+	return rv.Convert(basicTypes[flagTypeOf[T]()]).Interface()
+}
+
+// Default sets the default value for the flag. Numbers are converted to
+// the type of numeric flags, so Default(5) suits an int8 flag, and it
+// panics when the number does not fit.
 func (f *Flag) Default(v any) *Flag {
+	if f.isNumeric() {
+		n, err := convertNumber(v, f.Type)
+		if err != nil {
+			panic(fmt.Sprintf("invalid default %v for %s flag %s: %v", v, f.Type, f.Name, err))
+		}
+		v = n
+	}
 	f.DefValue = v
+	f.hasDefault = true
 	return f
 }
 
+// convertNumber converts a number of any Go numeric type to the type of
+// flag type t, failing when it does not fit. Other values are returned as is.
+func convertNumber(v any, t FlagType) (any, error) {
+	rv := reflect.ValueOf(v)
+	var s string
+	switch {
+	case !rv.IsValid():
+		return v, nil
+	case rv.CanInt():
+		s = strconv.FormatInt(rv.Int(), 10)
+	case rv.CanUint():
+		s = strconv.FormatUint(rv.Uint(), 10)
+	case rv.CanFloat():
+		s = strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	default:
+		return v, nil
+	}
+
+	dst := reflect.New(basicTypes[t]).Elem()
+	if err := convertValue(dst, s); err != nil {
+		return nil, err
+	}
+	return dst.Interface(), nil
+}
+
 // Required marks the flag as required
 func (f *Flag) Required() *Flag {
 	f.IsRequired = true
@@ -76,26 +217,60 @@ func (f *Flag) Help(text string) *Flag {
 	return f
 }
 
-// Choices only valid for string flags.
-func (f *Flag) Choices(opts ...string) *Flag {
-	if f.Type != StringType {
-		panic("Choices can only be used on string flags")
+// Env sets environment variables to read, in order, when the flag is not given
+func (f *Flag) Env(names ...string) *Flag {
+	f.EnvVars = append(f.EnvVars, names...)
+	return f
+}
+
+// AcceptLiterals makes the integer flag accept Go integer literals:
+// 0x, 0o, 0b and leading-zero octal prefixes and "_" digit separators
+func (f *Flag) AcceptLiterals() *Flag {
+	if !f.Type.isSigned() && !f.Type.isUnsigned() {
+		panic("AcceptLiterals can only be used on integer flags")
 	}
+	f.IntLiterals = true
+	return f
+}
+
+// Meta sets the value placeholder shown in help, e.g. "WHEN"
+func (f *Flag) Meta(name string) *Flag {
+	f.MetaVar = name
+	return f
+}
+
+// Optional lets the flag be used without a value, implying v.
+// A value can only be attached with "=", the next argument is never consumed.
+func (f *Flag) Optional(v string) *Flag {
+	if f.Type == BoolType {
+		panic("Optional cannot be used on bool flags")
+	}
+	f.IsOptional = true
+	f.NoOptDefault = v
+	return f
+}
+
+// Secret marks the value as sensitive, e.g. a password or token
+func (f *Flag) Secret() *Flag {
+	f.IsSecret = true
+	return f
+}
 
-	f.choices = make(map[string]struct{}, len(opts))
-	for _, o := range opts {
-		f.choices[o] = struct{}{}
+// FromFile adds a companion flag --<name>-file whose value is a file to
+// read the value from, "-" meaning stdin. A trailing newline is removed.
+func (f *Flag) FromFile() *Flag {
+	if f.Type == BoolType {
+		panic("FromFile cannot be used on bool flags")
 	}
-	f.ChoicesOpt = opts
+	f.FileFlag = true
 	return f
 }
 
-// Range only valid for numeric flags.
-func (f *Flag) Range(min, max int) *Flag {
-	if f.Type != IntType && f.Type != UintType && f.Type != FloatType {
-		panic("Range can only be used on int/uint/float flags")
+// AllowHyphenValues lets the next argument be taken as value even when it starts with "-"
+func (f *Flag) AllowHyphenValues() *Flag {
+	if f.Type == BoolType {
+		panic("AllowHyphenValues cannot be used on bool flags")
 	}
-	f.Min = min
-	f.Max = max
+	f.AllowHyphen = true
 	return f
 }
@@ -79,19 +79,41 @@ func TestFlagMethods(t *testing.T) {
 		}
 	})
 
-	t.Run("Choices panic on non-string", func(t *testing.T) {
+	t.Run("Choices panic on bool", func(t *testing.T) {
 		defer func() {
 			if r := recover(); r == nil {
-				t.Error("Choices() should panic on non-string flag")
+				t.Error("Choices() should panic on bool flag")
 			}
 		}()
-		Paw[int]("count").Choices("1", "2", "3")
+		Paw[bool]("force").Choices("true")
+	})
+
+	t.Run("Choices panic on invalid number", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Choices() should panic on non-numeric choice for int flag")
+			}
+		}()
+		Paw[int]("count").Choices("1", "two")
 	})
 
 	t.Run("Range", func(t *testing.T) {
 		flag := Paw[int]("size").Range(1, 100)
-		if flag.Min != 1 || flag.Max != 100 {
-			t.Errorf("Range() = [%d, %d], want [1, 100]", flag.Min, flag.Max)
+		if flag.Min == nil || flag.Max == nil || flag.Min.Value != 1 || flag.Max.Value != 100 {
+			t.Errorf("Range() = [%v, %v], want [1, 100]", flag.Min, flag.Max)
+		}
+		if flag.Min.Exclusive || flag.Max.Exclusive {
+			t.Error("Range() bounds should be inclusive")
+		}
+	})
+
+	t.Run("Open bounds", func(t *testing.T) {
+		flag := Paw[float64]("ratio").Above(0).AtMost(1)
+		if !flag.Min.Exclusive || flag.Max.Exclusive {
+			t.Errorf("bounds = %+v, %+v, want (0, 1]", *flag.Min, *flag.Max)
+		}
+		if got := flag.rangeString(); got != "(0, 1]" {
+			t.Errorf("rangeString() = %s, want (0, 1]", got)
 		}
 	})
 
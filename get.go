@@ -0,0 +1,128 @@
+package paws
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Get returns the value of a flag as T, using the default when not given.
+// It fails on unknown flag names, when T does not match the flag type,
+// and when the value cannot be converted.
+func Get[T FlagTypeConstraint](r *ParseResult, name string) (T, error) {
+	var out T
+
+	f, err := lookupFlag[T](r, name)
+	if err != nil {
+		return out, err
+	}
+
+	value, ok := r.Flags[f.Name]
+	if !ok {
+		if f.DefValue == nil {
+			return out, nil
+		}
+		if value, ok = defaultValue(f); !ok {
+			return out, &ParseError{
+				Err:   ErrTypeMismatch,
+				Flag:  f.Name,
+				Cause: fmt.Errorf("default %v has type %T", f.DefValue, f.DefValue),
+			}
+		}
+		if f.Type == PathType || f.Type == FileType {
+			value = r.String(f.Name)
+		}
+	}
+
+	if err := convertValue(reflect.ValueOf(&out).Elem(), value); err != nil {
+		return out, errorFlagValue(f, value, err)
+	}
+	return out, nil
+}
+
+// MustGet is like Get but panics on error
+func MustGet[T FlagTypeConstraint](r *ParseResult, name string) T {
+	v, err := Get[T](r, name)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Lookup returns the value of a flag only when it was given, ok is false
+// when it was left at its default. Like MustGet, it panics on unknown flag
+// names, when T does not match the flag type, and on invalid values.
+func Lookup[T FlagTypeConstraint](r *ParseResult, name string) (T, bool) {
+	var zero T
+	if _, err := lookupFlag[T](r, name); err != nil {
+		panic(err)
+	}
+	if !r.Changed(name) {
+		return zero, false
+	}
+	return MustGet[T](r, name), true
+}
+
+// lookupFlag finds the flag called name and checks that T can hold its values
+func lookupFlag[T FlagTypeConstraint](r *ParseResult, name string) (*Flag, error) {
+	f := r.findFlag(name)
+	if f == nil {
+		return nil, errorUnknownFlag(name)
+	}
+	if !kindMatches(reflect.TypeFor[T]().Kind(), f.Type) {
+		return nil, &ParseError{
+			Err:   ErrTypeMismatch,
+			Flag:  f.Name,
+			Cause: fmt.Errorf("flag is %s, not %s", f.Type, reflect.TypeFor[T]()),
+		}
+	}
+	return f, nil
+}
+
+// kindMatches reports whether values of kind k can hold a flag of type t
+func kindMatches(k reflect.Kind, t FlagType) bool {
+	if t == PathType || t == FileType || t == EnumType {
+		return k == reflect.String
+	}
+	rt, ok := basicTypes[t]
+	return ok && rt.Kind() == k
+}
+
+// convertValue parses v into dst according to its kind
+func convertValue(dst reflect.Value, v string) error {
+	switch {
+	case dst.Kind() == reflect.Bool:
+		if !isValidBoolValue(v) {
+			return fmt.Errorf("invalid boolean value: '%s'", v)
+		}
+		dst.SetBool(parseBoolValue(v))
+
+	case dst.Kind() == reflect.String:
+		dst.SetString(v)
+
+	case dst.CanInt():
+		i, err := strconv.ParseInt(v, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetInt(i)
+
+	case dst.CanUint():
+		u, err := strconv.ParseUint(v, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetUint(u)
+
+	case dst.CanFloat():
+		x, err := strconv.ParseFloat(v, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(x)
+
+	default:
+		return fmt.Errorf("unsupported type %s", dst.Type())
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package paws
+
+import (
+	"errors"
+	"testing"
+)
+
+type level int
+
+func TestGet(t *testing.T) {
+	parser := New()
+	parser.AddFlags(
+		Paw[int]("port", "p").Default(8080),
+		Paw[string]("host"),
+		Paw[bool]("verbose", "v"),
+		Paw[uint]("size").Default(10),
+		Paw[float64]("ratio").Default(1),
+		Paw[int]("level"),
+		Paw[int]("broken").Default("x"),
+	)
+
+	result, err := parser.Parse([]string{"--host", "example.com", "-v", "--level", "3"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	t.Run("values", func(t *testing.T) {
+		if v, err := Get[int](result, "p"); err != nil || v != 8080 {
+			t.Errorf("Get[int](p) = %v, %v, want 8080", v, err)
+		}
+		if v, err := Get[string](result, "host"); err != nil || v != "example.com" {
+			t.Errorf("Get[string](host) = %v, %v", v, err)
+		}
+		if v, err := Get[bool](result, "verbose"); err != nil || !v {
+			t.Errorf("Get[bool](verbose) = %v, %v", v, err)
+		}
+		if v, err := Get[uint](result, "size"); err != nil || v != 10 {
+			t.Errorf("Get[uint](size) = %v, %v", v, err)
+		}
+		if v, err := Get[float64](result, "ratio"); err != nil || v != 1 {
+			t.Errorf("Get[float64](ratio) = %v, %v", v, err)
+		}
+		if v, err := Get[level](result, "level"); err != nil || v != 3 {
+			t.Errorf("Get[level](level) = %v, %v", v, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := Get[int](result, "prot"); !errors.Is(err, ErrUnknownFlag) {
+			t.Errorf("Get() unknown error = %v", err)
+		}
+		if _, err := Get[string](result, "port"); !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("Get() mismatch error = %v", err)
+		}
+		if _, err := Get[int](result, "broken"); !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("Get() bad default error = %v", err)
+		}
+
+		bad := &ParseResult{Flags: map[string]string{"port": "x"}, index: result.index}
+		if _, err := Get[int](bad, "port"); !errors.Is(err, ErrFlagValue) {
+			t.Errorf("Get() conversion error = %v", err)
+		}
+	})
+
+	t.Run("MustGet", func(t *testing.T) {
+		if MustGet[string](result, "host") != "example.com" {
+			t.Error("MustGet() returned wrong value")
+		}
+		defer func() {
+			if recover() == nil {
+				t.Error("MustGet() should panic on unknown flag")
+			}
+		}()
+		MustGet[int](result, "nope")
+	})
+
+	t.Run("Lookup", func(t *testing.T) {
+		if v, ok := Lookup[string](result, "host"); !ok || v != "example.com" {
+			t.Errorf("Lookup(host) = %v, %v", v, ok)
+		}
+		if v, ok := Lookup[int](result, "port"); ok || v != 0 {
+			t.Errorf("Lookup(port) = %v, %v, want not given", v, ok)
+		}
+	})
+
+	t.Run("Lookup misuse", func(t *testing.T) {
+		for _, tt := range []struct {
+			name string
+			call func()
+		}{
+			{"unknown flag", func() { Lookup[string](result, "hots") }},
+			{"type mismatch", func() { Lookup[bool](result, "host") }},
+			{"type mismatch on default", func() { Lookup[string](result, "port") }},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				defer func() {
+					if recover() == nil {
+						t.Error("Lookup() should panic")
+					}
+				}()
+				tt.call()
+			})
+		}
+	})
+}
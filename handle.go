@@ -0,0 +1,91 @@
+package paws
+
+import "fmt"
+
+// Handle is a typed reference to a flag, so its value can be read
+// without string keys
+type Handle[T FlagTypeConstraint] struct {
+	*Flag
+}
+
+// Var creates a flag of type T, registers it as a global flag on p
+// and returns its typed handle
+func Var[T FlagTypeConstraint](p *Parser, name string, aliases ...string) *Handle[T] {
+	h := &Handle[T]{Flag: Paw[T](name, aliases...)}
+	p.AddFlags(h.Flag)
+	return h
+}
+
+// BindVar is like Var but fills ptr during Parser.Parse, see ParseResult.Bind.
+// The current value of *ptr is used as the default.
+func BindVar[T FlagTypeConstraint](p *Parser, ptr *T, name string, aliases ...string) *Handle[T] {
+	h := &Handle[T]{Flag: Paw[T](name, aliases...)}
+	h.DefValue = baseValue(*ptr)
+	h.hasDefault = true
+	h.Bind(ptr)
+	p.AddFlags(h.Flag)
+	return h
+}
+
+// HandleOf returns a typed handle for an existing flag, e.g. a command flag.
+// It panics when T does not match the flag type.
+func HandleOf[T FlagTypeConstraint](f *Flag) *Handle[T] {
+	if t := flagTypeOf[T](); t != f.Type && !(t == StringType && (f.Type == PathType || f.Type == FileType)) {
+		panic(fmt.Sprintf("flag %s is %s, not %s", f.Name, f.Type, t))
+	}
+	return &Handle[T]{Flag: f}
+}
+
+// Bind makes ParseResult.Bind store the flag value in ptr, or the default when not given
+func (h *Handle[T]) Bind(ptr *T) *Handle[T] {
+	h.bind = func(r *ParseResult) error {
+		v, err := Get[T](r, h.Name)
+		if err != nil {
+			return err
+		}
+		*ptr = v
+		return nil
+	}
+	return h
+}
+
+// Value returns the flag value from r, or the default when not given
+func (h *Handle[T]) Value(r *ParseResult) T {
+	return MustGet[T](r, h.Name)
+}
+
+// Get returns the flag value from r, see Get
+func (h *Handle[T]) Get(r *ParseResult) (T, error) {
+	return Get[T](r, h.Name)
+}
+
+// Lookup returns the flag value from r only when it was given, see Lookup
+func (h *Handle[T]) Lookup(r *ParseResult) (T, bool) {
+	return Lookup[T](r, h.Name)
+}
+
+// Bind fills the variables bound to the flags in scope with their values,
+// or defaults when not given. Parser.Parse binds its results, and once a
+// result is bound ApplyConfig and PromptMissing bind it again after setting
+// values. Bound variables are shared by all results, so Bind is not safe
+// for concurrent use.
+func (r *ParseResult) Bind() error {
+	r.bound = true
+	for _, f := range r.scopeFlags() {
+		if f.bind == nil {
+			continue
+		}
+		if err := f.bind(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebind updates the bound variables after values changed, when r was bound before
+func (r *ParseResult) rebind() error {
+	if !r.bound {
+		return nil
+	}
+	return r.Bind()
+}
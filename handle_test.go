@@ -0,0 +1,136 @@
+package paws
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+type port int
+
+func TestVar(t *testing.T) {
+	parser := New()
+	host := Var[string](parser, "host", "H")
+	verbose := Var[bool](parser, "verbose", "v")
+	p := Var[port](parser, "port", "p")
+	p.Default(80)
+
+	result, err := parser.Parse([]string{"-H", "example.com", "-v"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got := host.Value(result); got != "example.com" {
+		t.Errorf("host = %q, want example.com", got)
+	}
+	if !verbose.Value(result) {
+		t.Error("verbose should be true")
+	}
+	if got := p.Value(result); got != 80 {
+		t.Errorf("port = %d, want 80", got)
+	}
+	if _, ok := p.Lookup(result); ok {
+		t.Error("Lookup(port) should report a default value as not given")
+	}
+}
+
+func TestBindVar(t *testing.T) {
+	var cfg struct {
+		Port    int
+		Name    string
+		Verbose bool
+		Force   bool
+		Ratio   float64
+	}
+	cfg.Port = 8080
+	cfg.Ratio = 0.5
+
+	parser := New()
+	BindVar(parser, &cfg.Port, "port", "p")
+	BindVar(parser, &cfg.Name, "name")
+	BindVar(parser, &cfg.Verbose, "verbose", "v")
+	BindVar(parser, &cfg.Ratio, "ratio")
+
+	force := HandleOf[bool](Paw[bool]("force", "f")).Bind(&cfg.Force)
+	parser.AddCommand([]string{"push"}, []*Flag{force.Flag})
+
+	if _, err := parser.Parse([]string{"push", "--name", "x", "-vf"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if cfg.Port != 8080 || cfg.Name != "x" || !cfg.Verbose || !cfg.Force || cfg.Ratio != 0.5 {
+		t.Errorf("cfg = %+v", cfg)
+	}
+
+	if _, err := parser.Parse([]string{"--port", "1"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if cfg.Port != 1 {
+		t.Errorf("Port = %d, want 1", cfg.Port)
+	}
+
+	if _, err := parser.Parse([]string{"--port", "x"}); !errors.Is(err, ErrFlagValue) {
+		t.Errorf("Parse() error = %v, want ErrFlagValue", err)
+	}
+}
+
+func TestBindLater(t *testing.T) {
+	var name string
+	parser := New()
+	BindVar(parser, &name, "name")
+
+	t.Run("compiled parse leaves variables alone", func(t *testing.T) {
+		result, err := parser.Compile().Parse([]string{"--name", "a"})
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if name != "" {
+			t.Fatalf("name = %q before Bind, want empty", name)
+		}
+		if err := result.ApplyConfig(map[string]string{}, "app.toml"); err != nil || name != "" {
+			t.Fatalf("ApplyConfig() bound an unbound result, name = %q, error = %v", name, err)
+		}
+		if err := result.Bind(); err != nil || name != "a" {
+			t.Errorf("Bind() name = %q, error = %v, want a", name, err)
+		}
+	})
+
+	t.Run("config is bound", func(t *testing.T) {
+		result, err := parser.Parse(nil)
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if err := result.ApplyConfig(map[string]string{"name": "from-config"}, "app.toml"); err != nil {
+			t.Fatalf("ApplyConfig() error = %v", err)
+		}
+		if name != "from-config" {
+			t.Errorf("name = %q, want from-config", name)
+		}
+	})
+}
+
+func TestBindConcurrentCompiled(t *testing.T) {
+	var port int
+	parser := New()
+	BindVar(parser, &port, "port")
+	c := parser.Compile()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			if _, err := c.Parse([]string{"--port", "1"}); err != nil {
+				t.Errorf("Parse() error = %v", err)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestHandleOfMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("HandleOf() should panic on type mismatch")
+		}
+	}()
+	HandleOf[int](Paw[string]("name"))
+}
@@ -0,0 +1,126 @@
+package paws
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// Usage returns the flag synopsis used in help, e.g. "-c, --color[=WHEN]"
+func (f *Flag) Usage() string {
+	var b strings.Builder
+
+	for _, a := range f.Aliases {
+		b.WriteString(dashed(a))
+		b.WriteString(", ")
+	}
+	b.WriteString(dashed(f.Name))
+
+	if f.Type == BoolType {
+		return b.String()
+	}
+
+	meta := f.placeholder()
+	if f.IsOptional {
+		b.WriteString("[=" + meta + "]")
+	} else {
+		b.WriteString(" " + meta)
+	}
+	return b.String()
+}
+
+// placeholder returns the value name shown in help
+func (f *Flag) placeholder() string {
+	if f.MetaVar != "" {
+		return f.MetaVar
+	}
+	if f.isNumeric() || f.Type == PathType || f.Type == FileType {
+		return strings.ToUpper(f.Type.String())
+	}
+	return "VALUE"
+}
+
+// description returns the help text followed by its annotations
+func (f *Flag) description() string {
+	var notes []string
+
+	if len(f.ChoicesOpt) > 0 {
+		notes = append(notes, "one of: "+strings.Join(f.ChoicesOpt, ", "))
+	}
+	if r := f.rangeString(); r != "" {
+		notes = append(notes, "range: "+r)
+	}
+	if f.IsOptional && f.NoOptDefault != "" {
+		notes = append(notes, "implied: "+f.NoOptDefault)
+	}
+	if len(f.EnvVars) > 0 {
+		notes = append(notes, "env: "+strings.Join(f.EnvVars, ", "))
+	}
+	if f.FileFlag {
+		notes = append(notes, "file: "+dashed(f.fileFlagName()))
+	}
+	if def := defaultString(f); def != "" {
+		notes = append(notes, "default: "+def)
+	}
+	if f.IsRequired {
+		notes = append(notes, "required")
+	}
+
+	if len(notes) == 0 {
+		return f.HelpText
+	}
+	s := "(" + strings.Join(notes, "; ") + ")"
+	if f.HelpText == "" {
+		return s
+	}
+	return f.HelpText + " " + s
+}
+
+// defaultString formats a non-zero default value, secret ones are never shown
+func defaultString(f *Flag) string {
+	if f.DefValue == nil || f.IsSecret || reflect.ValueOf(f.DefValue).IsZero() {
+		return ""
+	}
+	return fmt.Sprint(f.DefValue)
+}
+
+// WriteHelp writes the flag listing for cmd, or only global flags when cmd is nil
+func (p *Parser) WriteHelp(w io.Writer, cmd *CommandDef) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	if cmd != nil && len(cmd.Flags) > 0 {
+		fmt.Fprintln(tw, "Flags:")
+		writeFlags(tw, cmd.Flags)
+	}
+
+	if len(p.Flags) > 0 {
+		if cmd != nil && len(cmd.Flags) > 0 {
+			fmt.Fprintln(tw)
+			fmt.Fprintln(tw, "Global Flags:")
+		} else {
+			fmt.Fprintln(tw, "Flags:")
+		}
+		writeFlags(tw, p.Flags)
+	}
+
+	return tw.Flush()
+}
+
+func writeFlags(w io.Writer, flags []*Flag) {
+	for _, f := range flags {
+		fmt.Fprintf(w, "  %s\t%s\n", f.Usage(), f.description())
+		for _, o := range f.EnumOpts {
+			fmt.Fprintf(w, "      %s\t%s\n", o.Label(), o.Desc)
+		}
+	}
+}
+
+// dashed prefixes a flag name with one dash for single characters, two otherwise
+func dashed(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
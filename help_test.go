@@ -0,0 +1,60 @@
+package paws
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFlagUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		flag *Flag
+		want string
+	}{
+		{"bool", Paw[bool]("verbose", "v"), "-v, --verbose"},
+		{"string", Paw[string]("file", "f"), "-f, --file VALUE"},
+		{"int", Paw[int]("count"), "--count INT"},
+		{"meta", Paw[string]("user").Meta("NAME"), "--user NAME"},
+		{"optional", Paw[string]("color").Optional("always").Meta("WHEN"), "--color[=WHEN]"},
+		{"path", Path("config", "c"), "-c, --config PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.flag.Usage(); got != tt.want {
+				t.Errorf("Usage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteHelp(t *testing.T) {
+	parser := New()
+	parser.AddFlags(
+		Paw[bool]("verbose", "v").Help("verbose output"),
+		Paw[string]("color").Optional("always").Meta("WHEN").Default("auto").Help("colorize output"),
+	)
+	parser.AddCommand([]string{"build"}, []*Flag{
+		Paw[string]("mode").Choices("fast", "slow").Required(),
+	})
+
+	var b strings.Builder
+	if err := parser.WriteHelp(&b, parser.Commands[0]); err != nil {
+		t.Fatalf("WriteHelp() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"Flags:",
+		"--mode VALUE",
+		"(one of: fast, slow; required)",
+		"Global Flags:",
+		"-v, --verbose",
+		"--color[=WHEN]",
+		"colorize output (implied: always; default: auto)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("help output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package paws
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// LintCode identifies the kind of definition problem
+type LintCode string
+
+const (
+	LintEmptyName        LintCode = "empty-name"        // Flag or command without a name
+	LintDuplicateName    LintCode = "duplicate-name"    // Name or alias used by two flags
+	LintShadowed         LintCode = "shadowed"          // Command flag hidden by a global flag
+	LintDuplicateCommand LintCode = "duplicate-command" // Same command path registered twice
+	LintInvalidRange     LintCode = "invalid-range"     // Range with min greater than max
+	LintDefaultType      LintCode = "default-type"      // Default does not match the flag type
+	LintDefaultChoice    LintCode = "default-choice"    // Default not among the choices
+	LintDefaultRange     LintCode = "default-range"     // Default outside the range
+)
+
+// Severity tells whether a diagnostic makes the definitions wrong
+type Severity int
+
+const (
+	SeverityError   Severity = iota // Definitions are wrong
+	SeverityWarning                 // Definitions work, but likely not as intended
+)
+
+// Severity returns how serious problems of this kind are
+func (c LintCode) Severity() Severity {
+	if c == LintShadowed {
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+// Diagnostic describes a problem in the flag and command definitions
+type Diagnostic struct {
+	Code    LintCode // Kind of problem
+	Command []string // Command path, nil for global flags
+	Flag    string   // Flag name involved, if any
+	Message string   // Human readable description
+
+	cmdIndex  int // Index in Parser.Commands, -1 for global flags
+	flagIndex int // Index in the flags of the command or the parser, -1 when none
+}
+
+// String returns a formatted diagnostic
+func (d Diagnostic) String() string {
+	var where []string
+	if d.Command != nil {
+		where = append(where, "command "+strings.Join(d.Command, " "))
+	}
+	if d.Flag != "" {
+		where = append(where, "flag "+d.Flag)
+	}
+	if len(where) == 0 {
+		return fmt.Sprintf("%s: %s", d.Code, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", strings.Join(where, ", "), d.Code, d.Message)
+}
+
+// Lint reports every problem found in the flag and command definitions
+func (p *Parser) Lint() []Diagnostic {
+	var diags []Diagnostic
+
+	global := p.lintFlags(-1, nil, p.Flags, &diags)
+
+	var paths [][]string
+	for i, cmd := range p.Commands {
+		if len(cmd.Path) == 0 || slices.Contains(cmd.Path, "") {
+			diags = append(diags, Diagnostic{Code: LintEmptyName, Command: cmd.Path, Message: "command path has an empty word", cmdIndex: i, flagIndex: -1})
+		}
+		if slices.ContainsFunc(paths, func(path []string) bool { return slices.Equal(path, cmd.Path) }) {
+			diags = append(diags, Diagnostic{Code: LintDuplicateCommand, Command: cmd.Path, Message: "command registered more than once", cmdIndex: i, flagIndex: -1})
+			continue
+		}
+		paths = append(paths, cmd.Path)
+
+		local := p.lintFlags(i, cmd.Path, cmd.Flags, &diags)
+		for j, f := range cmd.Flags {
+			for _, n := range append([]string{f.Name}, f.Aliases...) {
+				if g, ok := global[n]; ok && local[n] == f {
+					diags = append(diags, Diagnostic{
+						Code:      LintShadowed,
+						Command:   cmd.Path,
+						Flag:      f.Name,
+						Message:   fmt.Sprintf("%s is hidden by global flag %s", dashed(n), g.Name),
+						cmdIndex:  i,
+						flagIndex: j,
+					})
+				}
+			}
+		}
+	}
+
+	return diags
+}
+
+// Validate returns a DefinitionError holding all lint diagnostics, if any
+func (p *Parser) Validate() error {
+	if diags := p.Lint(); len(diags) > 0 {
+		return &DefinitionError{Diagnostics: diags}
+	}
+	return nil
+}
+
+// mustBeValid panics on invalid definitions in strict mode
+func (p *Parser) mustBeValid() {
+	if !p.Strict {
+		return
+	}
+	if err := p.Validate(); err != nil {
+		panic(err.Error())
+	}
+}
+
+// lintFlags checks one set of flags and returns them indexed by name and alias,
+// index is the one of the command in Parser.Commands or -1 for global flags
+func (p *Parser) lintFlags(index int, cmd []string, flags []*Flag, diags *[]Diagnostic) map[string]*Flag {
+	seen := make(map[string]*Flag)
+	current := 0
+	report := func(code LintCode, f *Flag, format string, args ...any) {
+		*diags = append(*diags, Diagnostic{
+			Code:      code,
+			Command:   cmd,
+			Flag:      f.Name,
+			Message:   fmt.Sprintf(format, args...),
+			cmdIndex:  index,
+			flagIndex: current,
+		})
+	}
+
+	for i, f := range flags {
+		current = i
+		if f.Name == "" {
+			report(LintEmptyName, f, "flag has no name")
+		}
+
+		names := append([]string{f.Name}, f.Aliases...)
+		if f.FileFlag {
+			names = append(names, f.fileFlagName())
+		}
+		for _, n := range names {
+			if n == "" {
+				continue
+			}
+			if other, ok := seen[n]; ok && other != f {
+				report(LintDuplicateName, f, "%s is already used by flag %s", dashed(n), other.Name)
+				continue
+			}
+			seen[n] = f
+		}
+
+		p.lintConstraints(f, report)
+	}
+	return seen
+}
+
+// lintConstraints checks the range, choices and default of a flag
+func (p *Parser) lintConstraints(f *Flag, report func(LintCode, *Flag, string, ...any)) {
+	if f.emptyRange() {
+		report(LintInvalidRange, f, "range %s is empty", f.rangeString())
+	}
+
+	if f.DefValue == nil {
+		return
+	}
+
+	value, ok := defaultValue(f)
+	if !ok {
+		report(LintDefaultType, f, "default %v (%T) does not match the flag type", f.DefValue, f.DefValue)
+		return
+	}
+
+	// Zero defaults left by Paw only mean "unset"
+	if !f.hasDefault && reflect.ValueOf(f.DefValue).IsZero() {
+		return
+	}
+
+	if len(f.choices) == 0 && f.Type != EnumType && (f.Min == nil && f.Max == nil || f.emptyRange()) {
+		return
+	}
+	if err := p.validateFlagValue(f, value); err != nil {
+		code := LintDefaultRange
+		if len(f.choices) > 0 || f.Type == EnumType {
+			code = LintDefaultChoice
+		}
+		report(code, f, "default %s: %v", value, err)
+	}
+}
+
+// defaultValue formats the default as a flag value, reporting whether
+// its Go type is accepted by the getter for the flag type. Numbers of
+// numeric flags are accepted whatever their Go type when they fit.
+func defaultValue(f *Flag) (string, bool) {
+	switch v := f.DefValue.(type) {
+	case string:
+		return v, f.Type == StringType || f.Type == PathType || f.Type == FileType || f.Type == EnumType
+	case nil:
+		return "", false
+	}
+
+	if f.isNumeric() {
+		n, err := convertNumber(f.DefValue, f.Type)
+		if err != nil || reflect.TypeOf(n) != basicTypes[f.Type] {
+			return "", false
+		}
+		return fmt.Sprint(n), true
+	}
+
+	// Other defaults must have the exact type of the flag
+	if reflect.TypeOf(f.DefValue) != basicTypes[f.Type] {
+		return "", false
+	}
+	return fmt.Sprint(f.DefValue), true
+}
@@ -0,0 +1,94 @@
+package paws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	parser := New()
+	parser.AddFlags(
+		Paw[bool]("verbose", "v"),
+		Paw[bool]("version", "v"),
+		Paw[int]("jobs").Range(10, 1),
+		Paw[int]("port").Default("8080"),
+		Paw[string]("mode").Choices("fast", "slow").Default("medium"),
+		Paw[int]("level").Range(1, 5).Default(9),
+		Paw[int]("zero").Range(1, 5).Default(0),
+		Paw[string]("empty").Choices("a", "b").Default(""),
+	)
+	parser.AddCommand([]string{"run"}, []*Flag{
+		Paw[bool]("verbose"),
+		Paw[string]("x"),
+		Paw[string]("x"),
+	})
+	parser.AddCommand([]string{"run"}, nil)
+
+	want := map[LintCode]int{
+		LintDuplicateName:    2,
+		LintInvalidRange:     1,
+		LintDefaultType:      1,
+		LintDefaultChoice:    2,
+		LintDefaultRange:     2,
+		LintShadowed:         1,
+		LintDuplicateCommand: 1,
+	}
+
+	got := make(map[LintCode]int)
+	for _, d := range parser.Lint() {
+		got[d.Code]++
+		if warn := d.Code == LintShadowed; warn != (d.Code.Severity() == SeverityWarning) {
+			t.Errorf("%s severity = %v", d.Code, d.Code.Severity())
+		}
+	}
+	for code, n := range want {
+		if got[code] != n {
+			t.Errorf("%s diagnostics = %d, want %d", code, got[code], n)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("unexpected diagnostics: %v", parser.Lint())
+	}
+
+	err := parser.Validate()
+	var de *DefinitionError
+	if !errors.As(err, &de) || !errors.Is(err, ErrDefinition) {
+		t.Fatalf("Validate() error = %v, want DefinitionError", err)
+	}
+	if !strings.Contains(err.Error(), "command run, flag verbose: shadowed") {
+		t.Errorf("Validate() error missing shadowed diagnostic:\n%s", err)
+	}
+}
+
+func TestLintClean(t *testing.T) {
+	parser := New()
+	parser.AddFlags(
+		Paw[bool]("verbose", "v"),
+		Paw[int]("level").Range(1, 5).Default(3),
+		Paw[uint]("size").Default(10),
+		Paw[float64]("ratio").Default(1),
+		Paw[string]("mode").Choices("fast", "slow").Default("fast"),
+		Paw[int]("jobs").Range(1, 8),
+		Paw[string]("format").Choices("json", "text"),
+		Path("config"),
+	)
+	parser.AddCommand([]string{"run"}, []*Flag{Paw[bool]("force", "f")})
+
+	if err := parser.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	parser := New()
+	parser.Strict = true
+	parser.AddFlags(Paw[bool]("verbose", "v"))
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("AddFlags() should panic on duplicate alias in strict mode")
+		}
+	}()
+	parser.AddFlags(Paw[bool]("version", "v"))
+}
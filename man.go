@@ -0,0 +1,343 @@
+package paws
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ManOptions controls the header and naming of generated man pages
+type ManOptions struct {
+	Name        string // Program name, e.g. "tool"
+	Section     string // Manual section, "1" when empty
+	Date        string // Date shown in the footer
+	Source      string // Source of the program, e.g. "tool 1.2.0"
+	Manual      string // Title of the manual, e.g. "User Commands"
+	Description string // Description of the program for the root page
+}
+
+// section returns the manual section, defaulting to 1
+func (o ManOptions) section() string {
+	if o.Section == "" {
+		return "1"
+	}
+	return o.Section
+}
+
+// pageName returns the man page name of cmd, e.g. "tool-remote-add"
+func (o ManOptions) pageName(cmd *CommandDef) string {
+	return strings.Join(commandWords(o.Name, cmd), "-")
+}
+
+// WriteMan writes the man page of cmd, or of the program itself when cmd is nil
+func (p *Parser) WriteMan(w io.Writer, cmd *CommandDef, opts ManOptions) error {
+	if cmd == nil && opts.Name == "" {
+		return fmt.Errorf("%w: man page of the program requires a name", ErrDefinition)
+	}
+
+	bw := bufio.NewWriter(w)
+	m := &manWriter{w: bw, p: p, opts: opts}
+
+	m.header(cmd)
+	m.synopsis(".SH", cmd)
+	m.description(cmd)
+	m.arguments(".SH", cmd)
+	if cmd == nil {
+		m.options(".SH", "OPTIONS", p.Flags)
+		m.commands(p.childrenOf(nil))
+	} else {
+		m.options(".SH", "OPTIONS", cmd.Flags)
+		m.options(".SH", "GLOBAL OPTIONS", p.Flags)
+		m.commands(p.childrenOf(cmd))
+	}
+	m.exitStatus()
+	m.seeAlso(cmd)
+
+	return bw.Flush()
+}
+
+// WriteManCombined writes a single man page describing the program and all of its commands
+func (p *Parser) WriteManCombined(w io.Writer, opts ManOptions) error {
+	if opts.Name == "" {
+		return fmt.Errorf("%w: man page of the program requires a name", ErrDefinition)
+	}
+
+	bw := bufio.NewWriter(w)
+	m := &manWriter{w: bw, p: p, opts: opts}
+
+	m.header(nil)
+	m.synopsis(".SH", nil)
+	m.description(nil)
+	m.options(".SH", "GLOBAL OPTIONS", p.Flags)
+
+	if len(p.Commands) > 0 {
+		m.line(".SH COMMANDS")
+		for _, cmd := range p.Commands {
+			m.line(".SS " + quote(cmd.Name()))
+			m.synopsis(".PP", cmd)
+			if cmd.HelpText != "" {
+				m.line(".PP")
+				m.text(cmd.HelpText)
+			}
+			m.arguments(".PP", cmd)
+			m.options(".PP", "Options:", cmd.Flags)
+		}
+	}
+	m.exitStatus()
+
+	return bw.Flush()
+}
+
+// GenManTree writes one man page per command into dir, named after the page,
+// e.g. "tool.1" and "tool-remote-add.1"
+func (p *Parser) GenManTree(dir string, opts ManOptions) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	cmds := append([]*CommandDef{nil}, p.Commands...)
+	for _, cmd := range cmds {
+		path := filepath.Join(dir, opts.pageName(cmd)+"."+opts.section())
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		err = p.WriteMan(f, cmd, opts)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manWriter emits roff for a single page
+type manWriter struct {
+	w    *bufio.Writer
+	p    *Parser
+	opts ManOptions
+}
+
+func (m *manWriter) line(s string) {
+	m.w.WriteString(s)
+	m.w.WriteByte('\n')
+}
+
+// text writes free text, escaping every line
+func (m *manWriter) text(s string) {
+	for _, l := range strings.Split(strings.TrimSpace(s), "\n") {
+		m.line(roffLine(l))
+	}
+}
+
+func (m *manWriter) header(cmd *CommandDef) {
+	m.line(fmt.Sprintf(".TH %s %s %s %s %s",
+		quote(strings.ToUpper(m.opts.pageName(cmd))),
+		quote(m.opts.section()),
+		quote(m.opts.Date),
+		quote(m.opts.Source),
+		quote(m.opts.Manual),
+	))
+
+	m.line(".SH NAME")
+	name := roffEscape(m.opts.pageName(cmd))
+	desc := m.opts.Description
+	if cmd != nil {
+		desc = cmd.HelpText
+	}
+	if desc = summary(desc); desc != "" {
+		name += " \\- " + roffEscape(desc)
+	}
+	m.line(name)
+}
+
+// synopsis writes the command words, required flags, [OPTIONS] and positionals
+func (m *manWriter) synopsis(macro string, cmd *CommandDef) {
+	if macro == ".SH" {
+		m.line(".SH SYNOPSIS")
+	} else {
+		m.line(macro)
+	}
+
+	m.line(".B " + roffEscape(strings.Join(commandWords(m.opts.Name, cmd), " ")))
+
+	required := m.p.requiredFlags(cmd)
+	if len(m.p.scopeFlags(cmd)) > len(required) {
+		m.line("[\\fIOPTIONS\\fR]")
+	}
+	for _, f := range required {
+		m.line(roffFlag(f))
+	}
+
+	if cmd == nil {
+		if len(m.p.childrenOf(nil)) > 0 {
+			m.line("\\fICOMMAND\\fR")
+		}
+		return
+	}
+	for _, a := range cmd.Positionals {
+		m.line(roffEscape(a.Usage()))
+	}
+}
+
+func (m *manWriter) description(cmd *CommandDef) {
+	desc := m.opts.Description
+	if cmd != nil {
+		desc = cmd.HelpText
+	}
+	if desc == "" {
+		return
+	}
+	m.line(".SH DESCRIPTION")
+	m.text(desc)
+}
+
+func (m *manWriter) arguments(macro string, cmd *CommandDef) {
+	if cmd == nil || len(cmd.Positionals) == 0 {
+		return
+	}
+	if macro == ".SH" {
+		m.line(".SH ARGUMENTS")
+	} else {
+		m.line(macro)
+		m.line("Arguments:")
+	}
+	for _, a := range cmd.Positionals {
+		m.line(".TP")
+		m.line("\\fB" + roffEscape(a.Usage()) + "\\fR")
+		if a.HelpText != "" {
+			m.text(a.HelpText)
+		}
+	}
+}
+
+func (m *manWriter) options(macro, title string, flags []*Flag) {
+	if len(flags) == 0 {
+		return
+	}
+	if macro == ".SH" {
+		m.line(".SH " + title)
+	} else {
+		m.line(macro)
+		m.line(title)
+	}
+
+	for _, f := range flags {
+		m.line(".TP")
+		m.line(roffFlag(f))
+		if desc := f.description(); desc != "" {
+			m.text(desc)
+		}
+		if len(f.EnumOpts) > 0 {
+			m.line(".RS")
+			for _, o := range f.EnumOpts {
+				m.line(".TP")
+				m.line("\\fB" + roffEscape(o.Label()) + "\\fR")
+				if o.Desc != "" {
+					m.text(o.Desc)
+				}
+			}
+			m.line(".RE")
+		}
+	}
+}
+
+func (m *manWriter) commands(children []*CommandDef) {
+	if len(children) == 0 {
+		return
+	}
+	m.line(".SH COMMANDS")
+	for _, c := range children {
+		m.line(".TP")
+		m.line(m.reference(c))
+		if s := summary(c.HelpText); s != "" {
+			m.line(roffLine(s))
+		}
+	}
+}
+
+func (m *manWriter) exitStatus() {
+	m.line(".SH EXIT STATUS")
+	for _, s := range [][2]string{
+		{"0", "Successful completion."},
+		{"1", "An error occurred."},
+		{"2", "Invalid command line usage."},
+	} {
+		m.line(".TP")
+		m.line(".B " + s[0])
+		m.line(s[1])
+	}
+}
+
+// seeAlso links the parent, sibling and child pages
+func (m *manWriter) seeAlso(cmd *CommandDef) {
+	if m.opts.Name == "" {
+		return
+	}
+
+	var refs []string
+	if cmd != nil {
+		refs = append(refs, m.reference(m.p.parentOf(cmd)))
+	}
+	for _, c := range m.p.siblingsOf(cmd) {
+		refs = append(refs, m.reference(c))
+	}
+	for _, c := range m.p.childrenOf(cmd) {
+		refs = append(refs, m.reference(c))
+	}
+
+	if len(refs) == 0 {
+		return
+	}
+	m.line(".SH SEE ALSO")
+	m.line(strings.Join(refs, ",\n"))
+}
+
+// reference formats a cross reference to the page of cmd, e.g. "\fBtool\-add\fR(1)"
+func (m *manWriter) reference(cmd *CommandDef) string {
+	return "\\fB" + roffEscape(m.opts.pageName(cmd)) + "\\fR(" + m.opts.section() + ")"
+}
+
+// roffFlag formats the flag synopsis in bold with an italic placeholder
+func roffFlag(f *Flag) string {
+	var names []string
+	for _, n := range append(slices.Clip(f.Aliases), f.Name) {
+		names = append(names, "\\fB"+roffEscape(dashed(n))+"\\fR")
+	}
+	s := strings.Join(names, ", ")
+
+	if f.Type == BoolType {
+		return s
+	}
+	meta := "\\fI" + roffEscape(f.placeholder()) + "\\fR"
+	if f.IsOptional {
+		return s + "[=" + meta + "]"
+	}
+	return s + " " + meta
+}
+
+// roffEscape escapes backslashes and dashes
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	return strings.ReplaceAll(s, "-", `\-`)
+}
+
+// roffLine escapes a text line, protecting a leading control character
+func roffLine(s string) string {
+	s = roffEscape(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		return `\&` + s
+	}
+	return s
+}
+
+// quote formats a macro argument as a quoted string
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(roffEscape(s), `"`, `""`) + `"`
+}
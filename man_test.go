@@ -0,0 +1,104 @@
+package paws
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func manParser() *Parser {
+	parser := New()
+	parser.AddFlags(Paw[bool]("verbose", "v").Help("verbose output"))
+	parser.AddCommand([]string{"remote"}, nil).Help("Manage remotes")
+	parser.AddCommand([]string{"remote", "add"}, []*Flag{
+		Paw[string]("mode").Choices("fetch", "push").Default("fetch").Help("mirror mode"),
+		Paw[int]("depth").Range(1, 10).Env("DEPTH"),
+		Paw[string]("url").Required(),
+	}).Help("Add a remote").Args(Positional("name").Help("remote name"), Positional("extra").Optional().Variadic())
+	parser.AddCommand([]string{"remote", "remove"}, nil).Help("Remove a remote")
+	return parser
+}
+
+func TestWriteMan(t *testing.T) {
+	parser := manParser()
+	opts := ManOptions{Name: "tool", Date: "2024-01-01", Description: "A tool"}
+
+	var b strings.Builder
+	if err := parser.WriteMan(&b, parser.Commands[1], opts); err != nil {
+		t.Fatalf("WriteMan() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		`.TH "TOOL\-REMOTE\-ADD" "1" "2024\-01\-01"`,
+		".SH NAME\ntool\\-remote\\-add \\- Add a remote\n",
+		".SH SYNOPSIS\n.B tool remote add\n[\\fIOPTIONS\\fR]\n\\fB\\-\\-url\\fR \\fIVALUE\\fR\n<name>\n[extra]...\n",
+		".SH ARGUMENTS",
+		"(one of: fetch, push; default: fetch)",
+		"(range: [1, 10]; env: DEPTH)",
+		".SH GLOBAL OPTIONS\n.TP\n\\fB\\-v\\fR, \\fB\\-\\-verbose\\fR\n",
+		".SH EXIT STATUS",
+		".SH SEE ALSO\n\\fBtool\\-remote\\fR(1),\n\\fBtool\\-remote\\-remove\\fR(1)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteMan() missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteManRoot(t *testing.T) {
+	parser := manParser()
+
+	var b strings.Builder
+	if err := parser.WriteMan(&b, nil, ManOptions{Name: "tool", Description: ".hidden"}); err != nil {
+		t.Fatalf("WriteMan() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		".B tool\n[\\fIOPTIONS\\fR]\n\\fICOMMAND\\fR\n",
+		".SH DESCRIPTION\n\\&.hidden\n",
+		".SH COMMANDS\n.TP\n\\fBtool\\-remote\\fR(1)\nManage remotes\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteMan() missing %q in:\n%s", want, out)
+		}
+	}
+
+	if err := parser.WriteMan(&b, nil, ManOptions{}); err == nil {
+		t.Error("WriteMan() without a name should fail for the root page")
+	}
+}
+
+func TestWriteManCombined(t *testing.T) {
+	parser := manParser()
+
+	var b strings.Builder
+	if err := parser.WriteManCombined(&b, ManOptions{Name: "tool"}); err != nil {
+		t.Fatalf("WriteManCombined() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{`.SS "remote"`, `.SS "remote add"`, `.SS "remote remove"`, "\\fB\\-\\-depth\\fR \\fIINT\\fR"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteManCombined() missing %q", want)
+		}
+	}
+	if strings.Count(out, ".TH ") != 1 {
+		t.Error("WriteManCombined() should write a single page")
+	}
+}
+
+func TestGenManTree(t *testing.T) {
+	dir := t.TempDir()
+	if err := manParser().GenManTree(dir, ManOptions{Name: "tool"}); err != nil {
+		t.Fatalf("GenManTree() error = %v", err)
+	}
+
+	for _, name := range []string{"tool.1", "tool-remote.1", "tool-remote-add.1", "tool-remote-remove.1"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("GenManTree() did not write %s", name)
+		}
+	}
+}
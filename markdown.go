@@ -0,0 +1,248 @@
+package paws
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// MarkdownOptions controls the content and naming of generated Markdown files
+type MarkdownOptions struct {
+	Name        string // Program name, e.g. "tool"
+	Description string // Description of the program for the root file
+
+	// FrontMatter returns text written at the top of the file of cmd,
+	// e.g. a YAML block for a static site generator. nil means none.
+	FrontMatter func(cmd *CommandDef) string
+
+	// FileName returns the file name of cmd, nil cmd being the program itself.
+	// It may hold directories separated by slashes, links between pages are
+	// relative. The default joins the program name and the command path with
+	// dashes, e.g. "tool-remote-add.md".
+	FileName func(cmd *CommandDef) string
+}
+
+// fileName returns the file name of cmd
+func (o MarkdownOptions) fileName(cmd *CommandDef) string {
+	if o.FileName != nil {
+		return o.FileName(cmd)
+	}
+	return strings.Join(commandWords(o.Name, cmd), "-") + ".md"
+}
+
+// link returns the path of the file of to relative to the file of from
+func (o MarkdownOptions) link(from, to *CommandDef) string {
+	target := o.fileName(to)
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(o.fileName(from))), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	return filepath.ToSlash(rel)
+}
+
+// WriteMarkdown writes the Markdown reference of cmd, or of the program itself when cmd is nil
+func (p *Parser) WriteMarkdown(w io.Writer, cmd *CommandDef, opts MarkdownOptions) error {
+	var b bytes.Buffer
+
+	if opts.FrontMatter != nil {
+		if fm := opts.FrontMatter(cmd); fm != "" {
+			b.WriteString(strings.TrimRight(fm, "\n") + "\n\n")
+		}
+	}
+
+	title := strings.Join(commandWords(opts.Name, cmd), " ")
+	desc := opts.Description
+	if cmd != nil {
+		desc = cmd.HelpText
+	}
+
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if desc != "" {
+		fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(desc))
+	}
+	fmt.Fprintf(&b, "## Usage\n\n```\n%s\n```\n\n", p.usageLine(opts.Name, cmd))
+
+	if cmd != nil && len(cmd.Positionals) > 0 {
+		b.WriteString("## Arguments\n\n| Argument | Description |\n| --- | --- |\n")
+		for _, a := range cmd.Positionals {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", a.Usage(), cell(a.HelpText))
+		}
+		b.WriteString("\n")
+	}
+
+	if cmd == nil {
+		writeFlagTable(&b, "Options", p.Flags)
+	} else {
+		writeFlagTable(&b, "Options", cmd.Flags)
+		writeFlagTable(&b, "Global Options", p.Flags)
+	}
+
+	if children := p.childrenOf(cmd); len(children) > 0 {
+		b.WriteString("## Commands\n\n")
+		for _, c := range children {
+			fmt.Fprintf(&b, "* [%s](%s)", strings.Join(commandWords(opts.Name, c), " "), opts.link(cmd, c))
+			if s := summary(c.HelpText); s != "" {
+				b.WriteString(" - " + s)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	// Without a name the program page has no title to link with
+	if parent := p.parentOf(cmd); cmd != nil && (parent != nil || opts.Name != "") {
+		fmt.Fprintf(&b, "## See Also\n\n* [%s](%s)\n", strings.Join(commandWords(opts.Name, parent), " "), opts.link(cmd, parent))
+	}
+
+	out := bytes.TrimRight(b.Bytes(), "\n")
+	_, err := w.Write(append(out, '\n'))
+	return err
+}
+
+// GenMarkdown returns the Markdown reference of the program and every command, by file name
+func (p *Parser) GenMarkdown(opts MarkdownOptions) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(p.Commands)+1)
+
+	for _, cmd := range append([]*CommandDef{nil}, p.Commands...) {
+		name := opts.fileName(cmd)
+		if _, ok := files[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate documentation file %s", ErrDefinition, name)
+		}
+
+		var b bytes.Buffer
+		if err := p.WriteMarkdown(&b, cmd, opts); err != nil {
+			return nil, err
+		}
+		files[name] = b.Bytes()
+	}
+	return files, nil
+}
+
+// WriteMarkdownTree writes the Markdown reference of every command into dir
+func (p *Parser) WriteMarkdownTree(dir string, opts MarkdownOptions) error {
+	files, err := p.GenMarkdown(opts)
+	if err != nil {
+		return err
+	}
+
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckMarkdownTree compares the files in dir with the generated reference,
+// failing with ErrStaleDocs when a file is missing or differs, or when a
+// Markdown file next to the generated ones would not be generated, e.g.
+// the page of a removed command. Other files in dir are ignored.
+func (p *Parser) CheckMarkdownTree(dir string, opts MarkdownOptions) error {
+	files, err := p.GenMarkdown(opts)
+	if err != nil {
+		return err
+	}
+
+	var stale []string
+	dirs := make(map[string]bool)
+	for name := range files {
+		dirs[filepath.Dir(filepath.FromSlash(name))] = true
+	}
+	for sub := range dirs {
+		found, err := filepath.Glob(filepath.Join(dir, sub, "*.md"))
+		if err != nil {
+			return err
+		}
+		for _, path := range found {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			if _, ok := files[filepath.ToSlash(rel)]; !ok {
+				stale = append(stale, filepath.ToSlash(rel)+" (not generated)")
+			}
+		}
+	}
+
+	for name, data := range files {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		switch {
+		case os.IsNotExist(err):
+			stale = append(stale, name+" (missing)")
+		case err != nil:
+			return err
+		case !bytes.Equal(got, data):
+			stale = append(stale, name)
+		}
+	}
+
+	if len(stale) > 0 {
+		slices.Sort(stale)
+		return fmt.Errorf("%w: %s", ErrStaleDocs, strings.Join(stale, ", "))
+	}
+	return nil
+}
+
+// writeFlagTable writes a table of flags under a heading
+func writeFlagTable(b *bytes.Buffer, title string, flags []*Flag) {
+	if len(flags) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "## %s\n\n| Flag | Type | Default | Required | Description |\n| --- | --- | --- | --- | --- |\n", title)
+	for _, f := range flags {
+		required := ""
+		if f.IsRequired {
+			required = "yes"
+		}
+		def := defaultString(f)
+		if def != "" {
+			def = "`" + def + "`"
+		}
+		fmt.Fprintf(b, "| `%s` | %s | %s | %s | %s |\n", f.Usage(), f.Type, cell(def), required, cell(flagNotes(f)))
+	}
+	b.WriteString("\n")
+}
+
+// flagNotes returns the help text followed by choices, range, environment variables and file flag
+func flagNotes(f *Flag) string {
+	parts := []string{f.HelpText}
+
+	if len(f.EnumOpts) > 0 {
+		var opts []string
+		for _, o := range f.EnumOpts {
+			s := "`" + o.Token + "`"
+			if o.Desc != "" {
+				s += " (" + o.Desc + ")"
+			}
+			opts = append(opts, s)
+		}
+		parts = append(parts, "One of: "+strings.Join(opts, ", ")+".")
+	} else if len(f.ChoicesOpt) > 0 {
+		parts = append(parts, "One of: `"+strings.Join(f.ChoicesOpt, "`, `")+"`.")
+	}
+	if r := f.rangeString(); r != "" {
+		parts = append(parts, "Range: `"+r+"`.")
+	}
+	if len(f.EnvVars) > 0 {
+		parts = append(parts, "Env: `"+strings.Join(f.EnvVars, "`, `")+"`.")
+	}
+	if f.FileFlag {
+		parts = append(parts, "File: `"+dashed(f.fileFlagName())+"`.")
+	}
+	return strings.TrimSpace(strings.Join(parts, " "))
+}
+
+// cell escapes text for use in a table cell
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
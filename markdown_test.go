@@ -0,0 +1,129 @@
+package paws
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	parser := manParser()
+	opts := MarkdownOptions{
+		Name:        "tool",
+		FrontMatter: func(cmd *CommandDef) string { return "---\ntitle: " + cmd.Name() + "\n---" },
+	}
+
+	var b strings.Builder
+	if err := parser.WriteMarkdown(&b, parser.Commands[1], opts); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"---\ntitle: remote add\n---\n\n# tool remote add\n\nAdd a remote\n",
+		"```\ntool remote add [options] --url VALUE <name> [extra]...\n```",
+		"| `<name>` | remote name |",
+		"| `--mode VALUE` | string | `fetch` |  | mirror mode One of: `fetch`, `push`. |",
+		"| `--depth INT` | int |  |  | Range: `[1, 10]`. Env: `DEPTH`. |",
+		"| `--url VALUE` | string |  | yes |  |",
+		"## Global Options",
+		"## See Also\n\n* [tool remote](tool-remote.md)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteMarkdown() missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteMarkdownRoot(t *testing.T) {
+	parser := manParser()
+	opts := MarkdownOptions{Name: "tool", FileName: func(cmd *CommandDef) string {
+		if cmd == nil {
+			return "index.md"
+		}
+		return strings.Join(cmd.Path, "/") + ".md"
+	}}
+
+	tests := []struct {
+		name string
+		cmd  *CommandDef
+		want string
+	}{
+		{"root", nil, "## Commands\n\n* [tool remote](remote.md) - Manage remotes\n"},
+		{"children", parser.Commands[0], "* [tool remote add](remote/add.md) - Add a remote\n"},
+		{"parent of nested", parser.Commands[1], "## See Also\n\n* [tool remote](../remote.md)\n"},
+		{"root of top level", parser.Commands[0], "## See Also\n\n* [tool](index.md)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := parser.WriteMarkdown(&b, tt.cmd, opts); err != nil {
+				t.Fatalf("WriteMarkdown() error = %v", err)
+			}
+			if !strings.Contains(b.String(), tt.want) {
+				t.Errorf("WriteMarkdown() missing %q in:\n%s", tt.want, b.String())
+			}
+		})
+	}
+
+	var b strings.Builder
+	if err := parser.WriteMarkdown(&b, parser.Commands[0], MarkdownOptions{}); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	if strings.Contains(b.String(), "See Also") {
+		t.Errorf("WriteMarkdown() without a name links the program page:\n%s", b.String())
+	}
+}
+
+func TestMarkdownTree(t *testing.T) {
+	parser := manParser()
+	opts := MarkdownOptions{Name: "tool"}
+	dir := t.TempDir()
+
+	if err := parser.CheckMarkdownTree(dir, opts); !errors.Is(err, ErrStaleDocs) {
+		t.Fatalf("CheckMarkdownTree() on empty dir error = %v, want ErrStaleDocs", err)
+	}
+
+	if err := parser.WriteMarkdownTree(dir, opts); err != nil {
+		t.Fatalf("WriteMarkdownTree() error = %v", err)
+	}
+	if err := parser.CheckMarkdownTree(dir, opts); err != nil {
+		t.Fatalf("CheckMarkdownTree() after write error = %v", err)
+	}
+
+	parser.Commands[1].Flags[0].Help("changed")
+	err := parser.CheckMarkdownTree(dir, opts)
+	if !errors.Is(err, ErrStaleDocs) || !strings.Contains(err.Error(), "tool-remote-add.md") {
+		t.Errorf("CheckMarkdownTree() after change error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "tool.md")); err != nil {
+		t.Errorf("WriteMarkdownTree() did not write tool.md")
+	}
+
+	// Pages of removed commands are stale, other files are left alone
+	if err := parser.WriteMarkdownTree(dir, opts); err != nil {
+		t.Fatalf("WriteMarkdownTree() error = %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "guide"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"notes.txt", "guide/intro.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := parser.CheckMarkdownTree(dir, opts); err != nil {
+		t.Fatalf("CheckMarkdownTree() with unrelated files error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tool-old.md"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err = parser.CheckMarkdownTree(dir, opts)
+	if !errors.Is(err, ErrStaleDocs) || !strings.Contains(err.Error(), "tool-old.md (not generated)") {
+		t.Errorf("CheckMarkdownTree() with a removed command error = %v", err)
+	}
+}
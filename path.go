@@ -0,0 +1,260 @@
+package paws
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PathCheck is a set of filesystem constraints for path flags
+type PathCheck int
+
+const (
+	PathExists    PathCheck = 1 << iota // Path must exist
+	PathNotExists                       // Path must not exist yet
+	PathIsFile                          // Path must be a regular file
+	PathIsDir                           // Path must be a directory
+	PathReadable                        // Path must be readable
+	PathWritable                        // Path must be writable
+)
+
+// FileMode selects how a file flag is opened
+type FileMode int
+
+const (
+	ReadMode  FileMode = iota // Open for reading, "-" is stdin
+	WriteMode                 // Create or truncate for writing, "-" is stdout
+)
+
+// Path creates a new filesystem path flag.
+// Values have "~" and environment variables expanded.
+func Path(name string, aliases ...string) *Flag {
+	return &Flag{
+		Name:     name,
+		Aliases:  aliases,
+		Type:     PathType,
+		DefValue: "",
+	}
+}
+
+// InFile creates a file flag opened for reading.
+// The file must exist and be readable, "-" means stdin.
+func InFile(name string, aliases ...string) *Flag {
+	return &Flag{
+		Name:      name,
+		Aliases:   aliases,
+		Type:      FileType,
+		DefValue:  "",
+		PathCheck: PathIsFile | PathReadable,
+		FileMode:  ReadMode,
+	}
+}
+
+// OutFile creates a file flag opened for writing.
+// The parent directory must exist and be writable, "-" means stdout.
+func OutFile(name string, aliases ...string) *Flag {
+	return &Flag{
+		Name:      name,
+		Aliases:   aliases,
+		Type:      FileType,
+		DefValue:  "",
+		PathCheck: PathWritable,
+		FileMode:  WriteMode,
+	}
+}
+
+// Check adds filesystem constraints, only valid for path and file flags.
+func (f *Flag) Check(c PathCheck) *Flag {
+	if f.Type != PathType && f.Type != FileType {
+		panic("Check can only be used on path/file flags")
+	}
+	f.PathCheck |= c
+	return f
+}
+
+// Absolute makes path values absolute, only valid for path and file flags.
+func (f *Flag) Absolute() *Flag {
+	if f.Type != PathType && f.Type != FileType {
+		panic("Absolute can only be used on path/file flags")
+	}
+	f.AbsPath = true
+	return f
+}
+
+// File returns the opened file for a file flag.
+// The file is opened on first use and the same handle is returned afterwards.
+func (r *ParseResult) File(n string) (*os.File, error) {
+	flag := r.findFlag(n)
+	if flag == nil {
+		return nil, errorUnknownFlag(n)
+	}
+	if flag.Type != FileType {
+		return nil, fmt.Errorf("flag %s is not a file flag", flag.Name)
+	}
+	if f, ok := r.files[flag.Name]; ok {
+		return f, nil
+	}
+
+	path := r.String(flag.Name)
+	if path == "" {
+		return nil, errorMissingValue(flag.Name)
+	}
+
+	var (
+		file *os.File
+		err  error
+	)
+
+	switch {
+	case path == "-" && flag.FileMode == WriteMode:
+		file = os.Stdout
+	case path == "-":
+		file = os.Stdin
+	case flag.FileMode == WriteMode:
+		file, err = os.Create(path)
+	default:
+		file, err = os.Open(path)
+	}
+	if err != nil {
+		return nil, errorFlagValue(flag, path, redactPath(flag, err))
+	}
+
+	if r.files == nil {
+		r.files = make(map[string]*os.File)
+	}
+	r.files[flag.Name] = file
+	return file, nil
+}
+
+// expandPath expands "~" and environment variables in a path value
+func (f *Flag) expandPath(v string) (string, error) {
+	if v == "" || isStdioValue(f, v) {
+		return v, nil
+	}
+
+	if v == "~" || strings.HasPrefix(v, "~/") || strings.HasPrefix(v, `~`+string(filepath.Separator)) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		v = home + v[1:]
+	}
+	v = os.ExpandEnv(v)
+
+	if f.AbsPath {
+		return filepath.Abs(v)
+	}
+	return v, nil
+}
+
+// isStdioValue reports whether v is "-" for a file flag
+func isStdioValue(f *Flag, v string) bool {
+	return f.Type == FileType && v == "-"
+}
+
+// checkPath validates a path value against the flag's constraints
+func checkPath(f *Flag, v string) error {
+	if isStdioValue(f, v) {
+		return nil
+	}
+	if v == "" {
+		return errors.New("empty path")
+	}
+
+	c := f.PathCheck
+	info, err := os.Stat(v)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return redactPath(f, err)
+	}
+	exists := err == nil
+
+	if c&PathNotExists != 0 {
+		if exists {
+			return fmt.Errorf("path '%s' already exists", shownValue(f, v))
+		}
+		return checkParentWritable(f, v)
+	}
+
+	// Writable files may be created when their directory allows it
+	if !exists && c&(PathExists|PathIsFile|PathIsDir|PathReadable) == 0 {
+		if c&PathWritable != 0 {
+			return checkParentWritable(f, v)
+		}
+		return nil
+	}
+
+	if !exists {
+		return fmt.Errorf("path '%s' does not exist", shownValue(f, v))
+	}
+	if c&PathIsFile != 0 && !info.Mode().IsRegular() {
+		return fmt.Errorf("path '%s' is not a regular file", shownValue(f, v))
+	}
+	if c&PathIsDir != 0 && !info.IsDir() {
+		return fmt.Errorf("path '%s' is not a directory", shownValue(f, v))
+	}
+	if f.Type == FileType && info.IsDir() {
+		return fmt.Errorf("path '%s' is a directory", shownValue(f, v))
+	}
+	if c&PathReadable != 0 && !canRead(v, info) {
+		return fmt.Errorf("path '%s' is not readable", shownValue(f, v))
+	}
+	if c&PathWritable != 0 && !canWrite(v, info) {
+		return fmt.Errorf("path '%s' is not writable", shownValue(f, v))
+	}
+	return nil
+}
+
+// checkParentWritable ensures a path could be created
+func checkParentWritable(f *Flag, v string) error {
+	dir := filepath.Dir(v)
+	info, err := os.Stat(dir)
+	shown := shownValue(f, dir)
+	if err != nil {
+		return fmt.Errorf("directory '%s' does not exist", shown)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("'%s' is not a directory", shown)
+	}
+	if !canWrite(dir, info) {
+		return fmt.Errorf("directory '%s' is not writable", shown)
+	}
+	return nil
+}
+
+// canRead tries to open a path for reading
+func canRead(v string, info fs.FileInfo) bool {
+	if info.IsDir() {
+		_, err := os.ReadDir(v)
+		return err == nil
+	}
+	f, err := os.Open(v)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
+// canWrite tries to open a file for writing without truncating it.
+// For a directory it creates and removes a temporary file, as permission
+// bits alone do not account for ACLs or read-only mounts.
+func canWrite(v string, info fs.FileInfo) bool {
+	if info.IsDir() {
+		f, err := os.CreateTemp(v, ".paws-*")
+		if err != nil {
+			return false
+		}
+		f.Close()
+		os.Remove(f.Name())
+		return true
+	}
+	f, err := os.OpenFile(v, os.O_WRONLY, 0)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
@@ -0,0 +1,168 @@
+package paws
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPathFlagChecks(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(file, []byte("x = 1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		flag    *Flag
+		value   string
+		wantErr bool
+	}{
+		{"existing file", Path("config").Check(PathIsFile), file, false},
+		{"missing file", Path("config").Check(PathExists), filepath.Join(dir, "nope"), true},
+		{"file is not dir", Path("dir").Check(PathIsDir), file, true},
+		{"dir is dir", Path("dir").Check(PathIsDir | PathWritable), dir, false},
+		{"must not exist", Path("out").Check(PathNotExists), file, true},
+		{"new path", Path("out").Check(PathNotExists), filepath.Join(dir, "new"), false},
+		{"new path missing parent", Path("out").Check(PathNotExists), filepath.Join(dir, "a", "b"), true},
+		{"readable file", Path("in").Check(PathReadable), file, false},
+		{"unchecked missing path", Path("any"), filepath.Join(dir, "nope"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := New()
+			parser.AddFlags(tt.flag)
+
+			_, err := parser.Parse([]string{"--" + tt.flag.Name, tt.value})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrFlagValue) {
+				t.Errorf("Parse() error = %v, want ErrFlagValue", err)
+			}
+		})
+	}
+}
+
+func TestPathExpansion(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	t.Setenv("PAWS_TEST_DIR", "/srv/data")
+
+	parser := New()
+	parser.AddFlags(
+		Path("home"),
+		Path("env"),
+		Path("abs").Absolute(),
+		Path("def").Default("~/.config"),
+	)
+
+	result, err := parser.Parse([]string{"--home", "~/notes", "--env", "$PAWS_TEST_DIR/x", "--abs", "rel"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got := result.String("home"); got != filepath.Join(home, "notes") {
+		t.Errorf("home = %s, want %s", got, filepath.Join(home, "notes"))
+	}
+	if got := result.String("env"); got != "/srv/data/x" {
+		t.Errorf("env = %s, want /srv/data/x", got)
+	}
+	if got := result.String("abs"); !filepath.IsAbs(got) {
+		t.Errorf("abs = %s, want absolute path", got)
+	}
+	if got := result.String("def"); got != filepath.Join(home, ".config") {
+		t.Errorf("def = %s, want %s", got, filepath.Join(home, ".config"))
+	}
+}
+
+func TestFileFlags(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.txt")
+	if err := os.WriteFile(in, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out.txt")
+
+	parser := New()
+	parser.AddFlags(InFile("input", "i"), OutFile("output", "o"))
+
+	t.Run("open lazily", func(t *testing.T) {
+		result, err := parser.Parse([]string{"-i", in, "-o", out})
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if _, err := os.Stat(out); err == nil {
+			t.Fatal("output file created before File() was called")
+		}
+
+		f, err := result.File("input")
+		if err != nil {
+			t.Fatalf("File() error = %v", err)
+		}
+		defer f.Close()
+		data, _ := io.ReadAll(f)
+		if string(data) != "hello" {
+			t.Errorf("input = %q, want hello", data)
+		}
+
+		again, _ := result.File("i")
+		if again != f {
+			t.Error("File() should return the same handle")
+		}
+
+		w, err := result.File("output")
+		if err != nil {
+			t.Fatalf("File() error = %v", err)
+		}
+		w.Close()
+		if _, err := os.Stat(out); err != nil {
+			t.Errorf("output file not created: %v", err)
+		}
+	})
+
+	t.Run("dash is stdio", func(t *testing.T) {
+		result, err := parser.Parse([]string{"--input", "-", "--output", "-"})
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if f, _ := result.File("input"); f != os.Stdin {
+			t.Error("input - should be stdin")
+		}
+		if f, _ := result.File("output"); f != os.Stdout {
+			t.Error("output - should be stdout")
+		}
+	})
+
+	t.Run("missing input", func(t *testing.T) {
+		_, err := parser.Parse([]string{"--input", filepath.Join(dir, "missing")})
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Flag != "input" {
+			t.Errorf("Parse() error = %v, want ParseError for input", err)
+		}
+	})
+
+	t.Run("output in missing dir", func(t *testing.T) {
+		_, err := parser.Parse([]string{"--output", filepath.Join(dir, "a", "out")})
+		if err == nil {
+			t.Error("Parse() should fail for output in missing directory")
+		}
+	})
+}
+
+func TestPositionalDash(t *testing.T) {
+	parser := New()
+	result, err := parser.Parse([]string{"-"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Positional) != 1 || result.Positional[0] != "-" {
+		t.Errorf("Positional = %v, want [-]", result.Positional)
+	}
+}
@@ -1,17 +1,31 @@
 package paws
 
 import (
+	"errors"
 	"fmt"
-	"math"
+	"os"
 	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
+)
+
+// Interspersal controls whether flags may follow positional arguments
+type Interspersal int
+
+const (
+	InheritInterspersal Interspersal = iota // Use the parser's setting, interspersed by default
+	Interspersed                            // Flags and positionals may be mixed freely
+	NonInterspersed                         // Parsing stops at the first positional argument
 )
 
 // CommandDef represents a command definition with its path and flags
 type CommandDef struct {
-	Path  []string // Command path (e.g., ["git", "commit"])
-	Flags []*Flag  // Command-specific flags
+	Path         []string     // Command path (e.g., ["git", "commit"])
+	Flags        []*Flag      // Command-specific flags
+	Interspersal Interspersal // Overrides the parser's interspersal
+	HelpText     string       // Command description
+	Positionals  []*Arg       // Positional argument definitions, used in docs
 }
 
 // ParseResult contains the result of parsing command line arguments
@@ -19,17 +33,39 @@ type ParseResult struct {
 	Command    *CommandDef       // Matched command (if any)
 	Positional []string          // Positional arguments
 	Flags      map[string]string // Parsed flag values
-	GlobalFlag map[string]*Flag  // Global flag definitions
+	GlobalFlag map[string]*Flag  // Global flag definitions, shared and read-only
 	DoubleDash bool              // Whether -- was encountered
 	RawArgs    []string          // Original arguments
+	Rest       []string          // Untouched arguments from the first positional in non-interspersed mode
+
+	Unknown     []string // Unknown flags in their original order, when allowed
+	Passthrough []string // Unknown flags, positionals and the rest in original order, when allowed
+
+	index   *Compiled
+	origins map[string]Origin
+	files   map[string]*os.File
+	hidden  map[int]int // Offsets of secret values in RawArgs by index
+	bound   bool        // Whether Bind was called, later changes are bound too
 }
 
 // Parser is the main argument parser
 type Parser struct {
-	Commands []*CommandDef // Registered commands
-	Flags    []*Flag       // Global flags
+	Commands     []*CommandDef // Registered commands
+	Flags        []*Flag       // Global flags
+	AllowAbbrev  bool          // Accept unique prefixes of long flags and commands
+	AllowUnknown bool          // Collect unknown flags instead of failing
+	Strict       bool          // Panic on registration when definitions are invalid
+	IntLiterals  bool          // Accept Go integer literals for all integer flags
+
+	Interspersal   Interspersal // Whether flags may follow positional arguments
+	PosixlyCorrect bool         // Stop at the first positional when POSIXLY_CORRECT is set
+
+	cache *compileCache
+}
 
-	flagIndex map[string]*Flag
+// compileCache holds the definitions last compiled by Parse
+type compileCache struct {
+	compiled atomic.Pointer[Compiled]
 }
 
 // New creates a new argument parser
@@ -37,36 +73,74 @@ func New() *Parser {
 	return &Parser{
 		Commands: []*CommandDef{},
 		Flags:    []*Flag{},
+		cache:    &compileCache{},
 	}
 }
 
 // AddCommand registers a new command with the parser
-func (p *Parser) AddCommand(path []string, flags []*Flag) {
-	p.Commands = append(p.Commands, &CommandDef{
+func (p *Parser) AddCommand(path []string, flags []*Flag) *CommandDef {
+	cmd := &CommandDef{
 		Path:  path,
 		Flags: flags,
-	})
+	}
+	p.Commands = append(p.Commands, cmd)
+	p.invalidate()
+	p.mustBeValid()
+	return cmd
 }
 
 // AddFlags registers global flags with the parser
 func (p *Parser) AddFlags(flags ...*Flag) {
 	p.Flags = append(p.Flags, flags...)
-	p.buildFlagMap()
+	p.invalidate()
+	p.mustBeValid()
 }
 
-// Parse parses command line arguments and returns a ParseResult
+// Parse parses command line arguments, fills the variables bound to flags
+// and returns a ParseResult. The compiled definitions are reused while the
+// registered flags, commands and settings stay the same. Changing the names
+// or aliases of a flag already registered is not noticed, use Compile after
+// such edits.
 func (p *Parser) Parse(args []string) (*ParseResult, error) {
+	result, err := p.compiled().Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Bind(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// compiled returns the cached compiled definitions, compiling them again when stale
+func (p *Parser) compiled() *Compiled {
+	if p.cache == nil {
+		return p.Compile()
+	}
+	if c := p.cache.compiled.Load(); c != nil && c.current(p) {
+		return c
+	}
+	c := p.Compile()
+	p.cache.compiled.Store(c)
+	return c
+}
+
+// invalidate drops the definitions compiled by Parse
+func (p *Parser) invalidate() {
+	if p.cache != nil {
+		p.cache.compiled.Store(nil)
+	}
+}
+
+// Parse parses command line arguments and returns a ParseResult.
+// Variables bound to flags are left alone, so parsing stays safe for
+// concurrent use, call ParseResult.Bind to fill them.
+func (c *Compiled) Parse(args []string) (*ParseResult, error) {
 	result := &ParseResult{
 		Flags:      make(map[string]string),
-		GlobalFlag: make(map[string]*Flag),
+		GlobalFlag: c.global,
 		RawArgs:    args,
-	}
-
-	for _, flag := range p.Flags {
-		result.GlobalFlag[flag.Name] = flag
-		for _, alias := range flag.Aliases {
-			result.GlobalFlag[alias] = flag
-		}
+		index:      c,
 	}
 
 	var (
@@ -76,7 +150,10 @@ func (p *Parser) Parse(args []string) (*ParseResult, error) {
 	)
 
 	// Step 1: Find command
-	cmd, consumed := p.findCommand(args)
+	cmd, consumed, err := c.findCommand(args)
+	if err != nil {
+		return nil, err
+	}
 	if cmd != nil {
 		result.Command = cmd
 		i = consumed
@@ -84,6 +161,7 @@ func (p *Parser) Parse(args []string) (*ParseResult, error) {
 
 	// Step 2: Parse flags and collect positional args
 	inPositional := false
+	interspersed := c.interspersed(cmd)
 	for i < len(args) {
 		arg := args[i]
 
@@ -91,53 +169,73 @@ func (p *Parser) Parse(args []string) (*ParseResult, error) {
 			inPositional = true
 			i++
 			result.DoubleDash = true
+			c.passthrough(result, arg)
 			continue
 		}
 
-		if !inPositional && strings.HasPrefix(arg, "-") {
-			consumed, err := p.parseFlag(arg, args, i, cmd, result)
+		if !inPositional && strings.HasPrefix(arg, "-") && arg != "-" && !c.isNegativeArg(arg, cmd) {
+			consumed, err := c.parseFlag(arg, args, i, cmd, result)
 			if err != nil {
+				// Unknown flags are kept as they are, including "=value"
+				if c.p.AllowUnknown && errors.Is(err, ErrUnknownFlag) {
+					result.Unknown = append(result.Unknown, arg)
+					c.passthrough(result, arg)
+					i++
+					continue
+				}
 				return nil, err
 			}
 			i += consumed
 			continue
 		}
 
+		// Without interspersal, everything from here on is left untouched
+		if !inPositional && !interspersed {
+			result.Rest = args[i:]
+			c.passthrough(result, result.Rest...)
+			break
+		}
+
 		// Positional argument
 		positional = append(positional, arg)
+		c.passthrough(result, arg)
 		i++
 	}
 
 	result.Positional = positional
+
+	// Step 3: Fill unset flags from the environment
+	if err := c.applyEnv(cmd, result); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
-// findCommand searches for the best matching command in the arguments
-func (p *Parser) findCommand(args []string) (*CommandDef, int) {
-	var bestMatch *CommandDef
-	bestLength := 0
+// passthrough records arguments to be forwarded when unknown flags are allowed
+func (c *Compiled) passthrough(result *ParseResult, args ...string) {
+	if c.p.AllowUnknown {
+		result.Passthrough = append(result.Passthrough, args...)
+	}
+}
 
-	for _, cmd := range p.Commands {
-		if len(cmd.Path) <= len(args) {
-			match := true
-			for j, part := range cmd.Path {
-				if j >= len(args) || args[j] != part {
-					match = false
-					break
-				}
-			}
-			if match && len(cmd.Path) > bestLength {
-				bestMatch = cmd
-				bestLength = len(cmd.Path)
-			}
+// interspersed reports whether flags may follow positionals for cmd
+func (c *Compiled) interspersed(cmd *CommandDef) bool {
+	if cmd != nil && cmd.Interspersal != InheritInterspersal {
+		return cmd.Interspersal == Interspersed
+	}
+	if c.p.Interspersal != InheritInterspersal {
+		return c.p.Interspersal == Interspersed
+	}
+	if c.p.PosixlyCorrect {
+		if _, ok := os.LookupEnv("POSIXLY_CORRECT"); ok {
+			return false
 		}
 	}
-
-	return bestMatch, bestLength
+	return true
 }
 
 // parseFlag handles both long and short flag parsing
-func (p *Parser) parseFlag(arg string, args []string, i int, cmd *CommandDef, result *ParseResult) (int, error) {
+func (c *Compiled) parseFlag(arg string, args []string, i int, cmd *CommandDef, result *ParseResult) (int, error) {
 	long := strings.HasPrefix(arg, "--")
 	nameStart := 2
 
@@ -147,19 +245,9 @@ func (p *Parser) parseFlag(arg string, args []string, i int, cmd *CommandDef, re
 
 	s := arg[nameStart:]
 
-	// Handle grouped short flags like -abc
+	// Handle grouped short flags like -abc, -n5 or -xvf archive.tar
 	if !long && len(s) > 1 {
-		for _, c := range s {
-			f := p.findFlag(string(c), cmd)
-			if f == nil {
-				return 0, errorUnknownFlag(string(c))
-			}
-			if f.Type != BoolType {
-				return 0, fmt.Errorf("non-boolean flag -%c cannot be grouped", c)
-			}
-			result.Flags[f.Name] = "true"
-		}
-		return 1, nil
+		return c.parseShortGroup(s, args, i, cmd, result)
 	}
 
 	// Handle --flag=value
@@ -171,106 +259,201 @@ func (p *Parser) parseFlag(arg string, args []string, i int, cmd *CommandDef, re
 		s = name
 	}
 
-	f := p.findFlag(s, cmd)
+	f := c.findFlag(s, cmd)
+	if f == nil && long && c.p.AllowAbbrev {
+		var err error
+		if f, err = c.findFlagPrefix(s, cmd); err != nil {
+			return 0, err
+		}
+	}
 	if f == nil {
 		return 0, errorUnknownFlag(s)
 	}
 
+	// Optional-value flags never consume the next argument
+	if !found && f.IsOptional {
+		if err := c.setFlag(f, f.NoOptDefault, result, cliAt(i)); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+
 	// Determine value if not via '='
-	if value == "" {
+	if !found {
 		// For boolean flags, check if next argument is a valid boolean value
 		if f.Type == BoolType {
 			// If there's a next argument and it's a valid boolean value, use it
 			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
 				nextArg := args[i+1]
 				if isValidBoolValue(nextArg) {
-					value = nextArg
-					if err := p.validateFlagValue(f, value); err != nil {
-						return 0, &ParseError{Err: ErrFlagValue, Flag: f.Name, Value: value, Cause: err}
+					if err := c.setFlag(f, nextArg, result, cliAt(i)); err != nil {
+						return 0, err
 					}
-					result.Flags[f.Name] = value
 					return 2, nil
 				}
 			}
 			// No explicit value provided, default to true
-			result.Flags[f.Name] = "true"
+			result.set(f, "true", cliAt(i))
 			return 1, nil
 		}
 
 		// Non-bool must have explicit value
-		if i+1 >= len(args) || strings.HasPrefix(args[i+1], "-") {
+		if i+1 >= len(args) || !acceptsValue(f, args[i+1]) {
 			return 0, errorMissingValue(s)
 		}
-		value = args[i+1]
-		consumed := 2
-		if err := p.validateFlagValue(f, value); err != nil {
-			return 0, &ParseError{Err: ErrFlagValue, Flag: f.Name, Value: value, Cause: err}
+		if err := c.setFlag(f, args[i+1], result, cliAt(i)); err != nil {
+			return 0, err
 		}
-		result.Flags[f.Name] = value
-		return consumed, nil
+		result.hide(f, i+1, 0)
+		return 2, nil
 	}
 
 	// Validate --flag=value
-	if err := p.validateFlagValue(f, value); err != nil {
-		return 0, &ParseError{Err: ErrFlagValue, Flag: f.Name, Value: value, Cause: err}
+	if err := c.setFlag(f, value, result, cliAt(i)); err != nil {
+		return 0, err
 	}
-	result.Flags[f.Name] = value
+	result.hide(f, i, len(arg)-len(value))
 	return 1, nil
 }
 
-func (p *Parser) buildFlagMap() {
-	if p.flagIndex == nil {
-		n := 0
-		for _, f := range p.Flags {
-			n += 1 + len(f.Aliases)
+// parseShortGroup handles a group of short flags with getopt semantics.
+// The first flag taking a value consumes the rest of the token, or the
+// next argument when it is the last character of the group.
+func (c *Compiled) parseShortGroup(s string, args []string, i int, cmd *CommandDef, result *ParseResult) (int, error) {
+	// Check the group before applying anything so it can be passed through whole
+	if c.p.AllowUnknown {
+		for _, ch := range s {
+			f := c.findFlag(string(ch), cmd)
+			if f == nil {
+				return 0, errorUnknownFlag(string(ch))
+			}
+			if f.Type != BoolType {
+				break
+			}
 		}
-		p.flagIndex = make(map[string]*Flag, len(p.Flags)*n)
 	}
-	clear(p.flagIndex)
-	for _, f := range p.Flags {
-		p.flagIndex[f.Name] = f
-		for _, a := range f.Aliases {
-			p.flagIndex[a] = f
+
+	for j, ch := range s {
+		name := string(ch)
+		f := c.findFlag(name, cmd)
+		if f == nil {
+			return 0, errorUnknownFlag(name)
+		}
+
+		if f.Type == BoolType {
+			result.set(f, "true", cliAt(i))
+			continue
+		}
+
+		// Rest of the token is the value, "-o=file" is accepted too
+		rest := strings.TrimPrefix(s[j+len(name):], "=")
+		if rest != "" {
+			if err := c.setFlag(f, rest, result, cliAt(i)); err != nil {
+				return 0, err
+			}
+			result.hide(f, i, len(args[i])-len(rest))
+			return 1, nil
 		}
+
+		if f.IsOptional {
+			if err := c.setFlag(f, f.NoOptDefault, result, cliAt(i)); err != nil {
+				return 0, err
+			}
+			return 1, nil
+		}
+
+		if i+1 >= len(args) || !acceptsValue(f, args[i+1]) {
+			return 0, errorMissingValue(name)
+		}
+		if err := c.setFlag(f, args[i+1], result, cliAt(i)); err != nil {
+			return 0, err
+		}
+		result.hide(f, i+1, 0)
+		return 2, nil
 	}
+	return 1, nil
 }
 
-// findFlag searches for a flag definition by name
-func (p *Parser) findFlag(name string, cmd *CommandDef) *Flag {
-	// Search in global flags first
-	if f, ok := p.flagIndex[name]; ok {
-		return f
+// setFlag normalizes and validates a flag value before storing it in result
+func (c *Compiled) setFlag(f *Flag, value string, result *ParseResult, o Origin) error {
+	if f.fileOf != nil {
+		return c.setFromFile(f, value, result, o)
 	}
-
-	// Search in command flags
-	if cmd != nil {
-		for _, flag := range cmd.Flags {
-			if flag.Name == name || slices.Contains(flag.Aliases, name) {
-				return flag
-			}
+	if f.Type == PathType || f.Type == FileType {
+		v, err := f.expandPath(value)
+		if err != nil {
+			return errorFlagValue(f, value, err)
 		}
+		value = v
 	}
 
+	if err := c.p.validateFlagValue(f, value); err != nil {
+		return errorFlagValue(f, value, err)
+	}
+	if f.Type == EnumType {
+		value, _ = f.enumToken(value)
+	}
+	result.set(f, c.p.canonicalInt(f, value), o)
 	return nil
 }
 
+// acceptsValue reports whether the argument v can be used as the value of f.
+// Dash-leading arguments are only taken as negative numbers by numeric
+// flags, as "-" by file flags, or by flags allowing hyphen values.
+func acceptsValue(f *Flag, v string) bool {
+	if !strings.HasPrefix(v, "-") || f.AllowHyphen || isStdioValue(f, v) {
+		return true
+	}
+	return f.isNumeric() && isNegativeNumber(v)
+}
+
+// isNegativeArg reports whether arg is a negative number to be treated as
+// positional, which is the case unless a digit short flag is defined
+func (c *Compiled) isNegativeArg(arg string, cmd *CommandDef) bool {
+	return isNegativeNumber(arg) && !c.commandIndex(cmd).digits
+}
+
+// isNegativeNumber checks if v looks like "-5", "-0.25" or "-1e3"
+func isNegativeNumber(v string) bool {
+	if len(v) < 2 || v[0] != '-' {
+		return false
+	}
+	if (v[1] < '0' || v[1] > '9') && v[1] != '.' {
+		return false
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return true
+	}
+	_, err := strconv.ParseInt(v, 0, 64)
+	return err == nil
+}
+
 // ValidateRequired checks if all required flags are provided
 func (p *Parser) ValidateRequired(result *ParseResult) error {
-	allFlags := p.Flags
+	if missing := p.missingRequired(result); len(missing) > 0 {
+		return errorRequiredFlag(missing[0].Name)
+	}
+	return nil
+}
+
+// missingRequired returns the required flags without a value, global flags first
+func (p *Parser) missingRequired(result *ParseResult) []*Flag {
+	allFlags := slices.Clip(p.Flags)
 
 	if result.Command != nil {
 		allFlags = append(allFlags, result.Command.Flags...)
 	}
 
+	var missing []*Flag
 	for _, flag := range allFlags {
 		if flag.IsRequired && flag.Type != BoolType {
 			value, exists := result.Flags[flag.Name]
 			if !exists || value == "" {
-				return errorRequiredFlag(flag.Name)
+				missing = append(missing, flag)
 			}
 		}
 	}
-	return nil
+	return missing
 }
 
 // validateFlagValue validates flag value based on its constraints
@@ -279,62 +462,88 @@ func (p *Parser) validateFlagValue(flag *Flag, value string) error {
 	case StringType:
 		if len(flag.choices) > 0 {
 			if _, ok := flag.choices[value]; !ok {
-				return fmt.Errorf("value '%s' not in allowed choices: %v", value, flag.ChoicesOpt)
+				return fmt.Errorf("value '%s' not in allowed choices: %v", shownValue(flag, value), flag.ChoicesOpt)
 			}
 		}
 
-	case IntType:
-		val, err := strconv.ParseInt(value, 10, 0)
+	case IntType, Int8Type, Int16Type, Int32Type, Int64Type:
+		val, err := strconv.ParseInt(value, p.intBase(flag), flag.Type.bitSize())
 		if err != nil {
-			return fmt.Errorf("invalid integer value: '%s'", value)
+			return intError(flag, value, err)
 		}
+		return flag.checkNumber(float64(val), strconv.FormatInt(val, 10), p.intBase(flag))
 
-		if val < math.MinInt || val > math.MaxInt {
-			return fmt.Errorf("integer overflow: %s", value)
-		}
-
-		v := int(val)
-
-		if flag.Min != 0 || flag.Max != 0 {
-			if v < flag.Min || v > flag.Max {
-				return fmt.Errorf("value %d out of range [%d, %d]", v, flag.Min, flag.Max)
-			}
-		}
-
-	case UintType:
-		val, err := strconv.ParseUint(value, 10, 0)
+	case UintType, Uint8Type, Uint16Type, Uint32Type, Uint64Type:
+		val, err := strconv.ParseUint(value, p.intBase(flag), flag.Type.bitSize())
 		if err != nil {
-			return fmt.Errorf("invalid unsigned integer value: '%s'", value)
-		}
-		if flag.Min != 0 || flag.Max != 0 {
-			// Ensure min is not negative for uint
-			m := max(0, flag.Min)
-			if val < uint64(m) || val > uint64(flag.Max) {
-				return fmt.Errorf("value %d out of range [%d, %d]", val, m, flag.Max)
-			}
+			return intError(flag, value, err)
 		}
+		return flag.checkNumber(float64(val), strconv.FormatUint(val, 10), p.intBase(flag))
 
 	case FloatType:
 		val, err := strconv.ParseFloat(value, 64)
 		if err != nil {
-			return fmt.Errorf("invalid float value: '%s'", value)
-		}
-		if flag.Min != 0 || flag.Max != 0 {
-			if val < float64(flag.Min) || val > float64(flag.Max) {
-				return fmt.Errorf("value %f out of range [%f, %f]", val, float64(flag.Min), float64(flag.Max))
-			}
+			return fmt.Errorf("invalid float value: '%s'", shownValue(flag, value))
 		}
+		return flag.checkNumber(val, strconv.FormatFloat(val, 'g', -1, 64), 10)
 
 	case BoolType:
 		// Boolean flags accept various truthy/falsy values
 		if !isValidBoolValue(value) {
-			return fmt.Errorf("invalid boolean value: '%s' (allowed: true/t/yes/y/false/f/no/n)", value)
+			return fmt.Errorf("invalid boolean value: '%s' (allowed: true/t/yes/y/false/f/no/n)", shownValue(flag, value))
+		}
+
+	case PathType, FileType:
+		return checkPath(flag, value)
+
+	case EnumType:
+		if _, ok := flag.enumToken(value); !ok {
+			return flag.enumError(value)
 		}
 	}
 
 	return nil
 }
 
+// intBase returns the base for parsing integer values of flag,
+// 0 selects Go literal syntax
+func (p *Parser) intBase(flag *Flag) int {
+	if p.IntLiterals || flag.IntLiterals {
+		return 0
+	}
+	return 10
+}
+
+// intError describes an integer parse failure, detecting overflow
+func intError(flag *Flag, value string, err error) error {
+	value = shownValue(flag, value)
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("%w: %s does not fit in %s", ErrOverflow, value, flag.Type)
+	}
+	if flag.Type.isUnsigned() {
+		return fmt.Errorf("invalid unsigned integer value: '%s'", value)
+	}
+	return fmt.Errorf("invalid integer value: '%s'", value)
+}
+
+// canonicalInt rewrites an integer literal in decimal, other values are returned as is
+func (p *Parser) canonicalInt(flag *Flag, value string) string {
+	if p.intBase(flag) == 10 {
+		return value
+	}
+	if flag.Type.isSigned() {
+		if v, err := strconv.ParseInt(value, 0, 64); err == nil {
+			return strconv.FormatInt(v, 10)
+		}
+	}
+	if flag.Type.isUnsigned() {
+		if v, err := strconv.ParseUint(value, 0, 64); err == nil {
+			return strconv.FormatUint(v, 10)
+		}
+	}
+	return value
+}
+
 // Bool returns boolean value for flag, using default value if not provided
 func (r *ParseResult) Bool(n string) bool {
 	if val, exists := r.Flags[n]; exists {
@@ -360,6 +569,11 @@ func (r *ParseResult) String(n string) string {
 	flag := r.findFlag(n)
 	if flag != nil && flag.DefValue != nil {
 		if s, ok := flag.DefValue.(string); ok {
+			if flag.Type == PathType || flag.Type == FileType {
+				if p, err := flag.expandPath(s); err == nil {
+					return p
+				}
+			}
 			return s
 		}
 	}
@@ -425,6 +639,10 @@ func (r *ParseResult) Float(n string) float64 {
 
 // findFlag searches for flag definition in both global and command flags
 func (r *ParseResult) findFlag(name string) *Flag {
+	if r.index != nil {
+		return r.index.findFlag(name, r.Command)
+	}
+
 	// Search in global flags
 	if flag, exists := r.GlobalFlag[name]; exists {
 		return flag
@@ -1,6 +1,8 @@
 package paws
 
 import (
+	"errors"
+	"slices"
 	"testing"
 )
 
@@ -52,8 +54,8 @@ func TestAddFlags(t *testing.T) {
 	if len(parser.Flags) != 2 {
 		t.Fatalf("Expected 2 flags, got %d", len(parser.Flags))
 	}
-	if parser.flagIndex == nil {
-		t.Error("flagIndex should be initialized after AddFlags")
+	if parser.Compile().findFlag("c", nil) == nil {
+		t.Error("flags should be indexed by alias after AddFlags")
 	}
 }
 
@@ -400,7 +402,6 @@ func TestFlagValidation(t *testing.T) {
 			}
 			// Clean up for next test
 			parser.Flags = nil
-			parser.flagIndex = nil
 		})
 	}
 }
@@ -637,16 +638,457 @@ func TestEdgeCases(t *testing.T) {
 		}
 	})
 
-	t.Run("non-boolean flag in group", func(t *testing.T) {
+	t.Run("non-boolean flag in group without value", func(t *testing.T) {
 		parser := New()
 		parser.AddFlags(
 			Paw[bool]("verbose", "v"),
 			Paw[string]("file", "f"),
 		)
 
-		_, err := parser.Parse([]string{"-vf", "filename"})
+		_, err := parser.Parse([]string{"-vf"})
 		if err == nil {
-			t.Error("Expected error for non-boolean flag in group")
+			t.Error("Expected error for non-boolean flag in group without value")
+		}
+	})
+}
+
+func TestOptionalValueFlags(t *testing.T) {
+	parser := New()
+	parser.AddFlags(
+		Paw[string]("color", "c").Optional("always").Default("auto"),
+		Paw[int]("level").Optional("3"),
+	)
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantColor string
+		wantLevel int
+		wantPos   []string
+	}{
+		{"absent", []string{}, "auto", 0, nil},
+		{"bare long", []string{"--color"}, "always", 0, nil},
+		{"bare short", []string{"-c"}, "always", 0, nil},
+		{"attached", []string{"--color=never"}, "never", 0, nil},
+		{"attached empty", []string{"--color="}, "", 0, nil},
+		{"next arg not consumed", []string{"--color", "never"}, "always", 0, []string{"never"}},
+		{"int implied", []string{"--level", "file"}, "auto", 3, []string{"file"}},
+		{"int attached", []string{"--level=5"}, "auto", 5, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := result.String("color"); got != tt.wantColor {
+				t.Errorf("color = %q, want %q", got, tt.wantColor)
+			}
+			if got := result.Int("level"); got != tt.wantLevel {
+				t.Errorf("level = %d, want %d", got, tt.wantLevel)
+			}
+			if len(result.Positional) != len(tt.wantPos) {
+				t.Errorf("Positional = %v, want %v", result.Positional, tt.wantPos)
+			}
+		})
+	}
+
+	t.Run("invalid attached value", func(t *testing.T) {
+		if _, err := parser.Parse([]string{"--level=x"}); err == nil {
+			t.Error("Parse() should fail for invalid optional value")
+		}
+	})
+}
+
+func TestShortGroupValues(t *testing.T) {
+	parser := New()
+	parser.AddFlags(
+		Paw[bool]("extract", "x"),
+		Paw[bool]("verbose", "v"),
+		Paw[string]("file", "f"),
+		Paw[int]("count", "n"),
+		Paw[string]("output", "o"),
+		Paw[string]("color", "c").Optional("always"),
+	)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[string]string
+		wantPos []string
+		wantErr bool
+	}{
+		{
+			name: "attached int",
+			args: []string{"-n5"},
+			want: map[string]string{"count": "5"},
+		},
+		{
+			name: "attached negative int",
+			args: []string{"-n-5"},
+			want: map[string]string{"count": "-5"},
+		},
+		{
+			name: "attached string",
+			args: []string{"-ofile"},
+			want: map[string]string{"output": "file"},
+		},
+		{
+			name: "attached with equals",
+			args: []string{"-o=file"},
+			want: map[string]string{"output": "file"},
+		},
+		{
+			name:    "tar style",
+			args:    []string{"-xvf", "archive.tar", "extra"},
+			want:    map[string]string{"extract": "true", "verbose": "true", "file": "archive.tar"},
+			wantPos: []string{"extra"},
+		},
+		{
+			name: "bools before attached value",
+			args: []string{"-xvfarchive.tar"},
+			want: map[string]string{"extract": "true", "verbose": "true", "file": "archive.tar"},
+		},
+		{
+			name: "repeated bools",
+			args: []string{"-vvv"},
+			want: map[string]string{"verbose": "true"},
+		},
+		{
+			name: "optional in group",
+			args: []string{"-vc"},
+			want: map[string]string{"verbose": "true", "color": "always"},
+		},
+		{
+			name:    "invalid attached int",
+			args:    []string{"-nx"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown in group",
+			args:    []string{"-vq"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for k, v := range tt.want {
+				if result.Flags[k] != v {
+					t.Errorf("Flags[%s] = %q, want %q", k, result.Flags[k], v)
+				}
+			}
+			if len(result.Positional) != len(tt.wantPos) {
+				t.Errorf("Positional = %v, want %v", result.Positional, tt.wantPos)
+			}
+		})
+	}
+}
+
+func TestNegativeNumbers(t *testing.T) {
+	parser := New()
+	parser.AddFlags(
+		Paw[int]("offset"),
+		Paw[float64]("delta", "d"),
+		Paw[string]("pattern"),
+		Paw[string]("grep").AllowHyphenValues(),
+		Paw[bool]("verbose", "v"),
+	)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[string]string
+		wantPos []string
+		wantErr bool
+	}{
+		{
+			name: "negative int",
+			args: []string{"--offset", "-5"},
+			want: map[string]string{"offset": "-5"},
+		},
+		{
+			name: "negative float short",
+			args: []string{"-d", "-0.25"},
+			want: map[string]string{"delta": "-0.25"},
+		},
+		{
+			name: "leading dot",
+			args: []string{"--delta", "-.5"},
+			want: map[string]string{"delta": "-.5"},
+		},
+		{
+			name:    "string refuses dash value",
+			args:    []string{"--pattern", "-foo"},
+			wantErr: true,
+		},
+		{
+			name: "hyphen values allowed",
+			args: []string{"--grep", "-foo"},
+			want: map[string]string{"grep": "-foo"},
+		},
+		{
+			name:    "int refuses flag",
+			args:    []string{"--offset", "-v"},
+			wantErr: true,
+		},
+		{
+			name:    "negative positional",
+			args:    []string{"-v", "-3", "-2.5"},
+			want:    map[string]string{"verbose": "true"},
+			wantPos: []string{"-3", "-2.5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for k, v := range tt.want {
+				if result.Flags[k] != v {
+					t.Errorf("Flags[%s] = %q, want %q", k, result.Flags[k], v)
+				}
+			}
+			if len(result.Positional) != len(tt.wantPos) {
+				t.Fatalf("Positional = %v, want %v", result.Positional, tt.wantPos)
+			}
+			for i := range tt.wantPos {
+				if result.Positional[i] != tt.wantPos[i] {
+					t.Errorf("Positional[%d] = %s, want %s", i, result.Positional[i], tt.wantPos[i])
+				}
+			}
+		})
+	}
+
+	t.Run("digit short flag wins", func(t *testing.T) {
+		parser := New()
+		parser.AddFlags(Paw[bool]("ipv4", "4"))
+		result, err := parser.Parse([]string{"-4"})
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if !result.Bool("ipv4") || len(result.Positional) != 0 {
+			t.Errorf("-4 should set ipv4, got flags %v positional %v", result.Flags, result.Positional)
+		}
+	})
+}
+
+func TestAbbreviation(t *testing.T) {
+	parser := New()
+	parser.AllowAbbrev = true
+	parser.AddFlags(
+		Paw[bool]("verbose", "v"),
+		Paw[bool]("verbatim"),
+		Paw[string]("output", "o"),
+	)
+	parser.AddCommand([]string{"tool", "status"}, []*Flag{Paw[bool]("short")})
+	parser.AddCommand([]string{"tool", "stash"}, nil)
+	parser.AddCommand([]string{"tool", "push"}, nil)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCmd    string
+		wantFlag   string
+		wantValue  string
+		candidates []string
+	}{
+		{name: "unique flag prefix", args: []string{"--out=x"}, wantFlag: "output", wantValue: "x"},
+		{name: "exact flag", args: []string{"--verbose"}, wantFlag: "verbose", wantValue: "true"},
+		{name: "ambiguous flag", args: []string{"--verb"}, candidates: []string{"verbatim", "verbose"}},
+		{name: "unique command prefix", args: []string{"tool", "pu"}, wantCmd: "push"},
+		{name: "command flag prefix", args: []string{"tool", "statu", "--sh"}, wantCmd: "status", wantFlag: "short", wantValue: "true"},
+		{name: "ambiguous command", args: []string{"tool", "st"}, candidates: []string{"stash", "status"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(tt.args)
+			if tt.candidates != nil {
+				var pe *ParseError
+				if !errors.As(err, &pe) || !errors.Is(err, ErrAmbiguous) {
+					t.Fatalf("Parse() error = %v, want ambiguous error", err)
+				}
+				if !slices.Equal(pe.Candidates, tt.candidates) {
+					t.Errorf("Candidates = %v, want %v", pe.Candidates, tt.candidates)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if tt.wantCmd != "" && (result.Command == nil || result.Command.Path[1] != tt.wantCmd) {
+				t.Errorf("Command = %v, want %s", result.Command, tt.wantCmd)
+			}
+			if tt.wantFlag != "" && result.Flags[tt.wantFlag] != tt.wantValue {
+				t.Errorf("Flags[%s] = %q, want %q", tt.wantFlag, result.Flags[tt.wantFlag], tt.wantValue)
+			}
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		parser := New()
+		parser.AddFlags(Paw[string]("output"))
+		if _, err := parser.Parse([]string{"--out", "x"}); !errors.Is(err, ErrUnknownFlag) {
+			t.Errorf("Parse() error = %v, want ErrUnknownFlag", err)
+		}
+	})
+}
+
+func TestInterspersal(t *testing.T) {
+	newParser := func() *Parser {
+		parser := New()
+		parser.AddFlags(Paw[bool]("verbose", "v"))
+		parser.AddCommand([]string{"tool", "exec"}, nil).Interspersal = NonInterspersed
+		parser.AddCommand([]string{"tool", "run"}, nil)
+		return parser
+	}
+
+	tests := []struct {
+		name     string
+		setup    func(*Parser)
+		env      bool
+		args     []string
+		wantPos  []string
+		wantRest []string
+		verbose  bool
+	}{
+		{
+			name:     "command stops at first positional",
+			args:     []string{"tool", "exec", "-v", "ls", "-la", "--", "x"},
+			wantRest: []string{"ls", "-la", "--", "x"},
+			verbose:  true,
+		},
+		{
+			name:    "other command stays interspersed",
+			args:    []string{"tool", "run", "a", "-v", "b"},
+			wantPos: []string{"a", "b"},
+			verbose: true,
+		},
+		{
+			name:     "parser setting",
+			setup:    func(p *Parser) { p.Interspersal = NonInterspersed },
+			args:     []string{"a", "-v"},
+			wantRest: []string{"a", "-v"},
+		},
+		{
+			name:    "double dash before stop",
+			setup:   func(p *Parser) { p.Interspersal = NonInterspersed },
+			args:    []string{"-v", "--", "a", "-v"},
+			wantPos: []string{"a", "-v"},
+			verbose: true,
+		},
+		{
+			name:     "posixly correct honoured",
+			setup:    func(p *Parser) { p.PosixlyCorrect = true },
+			env:      true,
+			args:     []string{"a", "-v"},
+			wantRest: []string{"a", "-v"},
+		},
+		{
+			name:    "posixly correct ignored without opt-in",
+			env:     true,
+			args:    []string{"a", "-v"},
+			wantPos: []string{"a"},
+			verbose: true,
+		},
+		{
+			name:    "command overrides posixly correct",
+			setup:   func(p *Parser) { p.PosixlyCorrect = true; p.Commands[1].Interspersal = Interspersed },
+			env:     true,
+			args:    []string{"tool", "run", "a", "-v"},
+			wantPos: []string{"a"},
+			verbose: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env {
+				t.Setenv("POSIXLY_CORRECT", "1")
+			}
+			parser := newParser()
+			if tt.setup != nil {
+				tt.setup(parser)
+			}
+
+			result, err := parser.Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !slices.Equal(result.Positional, tt.wantPos) {
+				t.Errorf("Positional = %v, want %v", result.Positional, tt.wantPos)
+			}
+			if !slices.Equal(result.Rest, tt.wantRest) {
+				t.Errorf("Rest = %v, want %v", result.Rest, tt.wantRest)
+			}
+			if result.Bool("verbose") != tt.verbose {
+				t.Errorf("verbose = %v, want %v", result.Bool("verbose"), tt.verbose)
+			}
+		})
+	}
+}
+
+func TestAllowUnknown(t *testing.T) {
+	parser := New()
+	parser.AllowUnknown = true
+	parser.AddFlags(
+		Paw[bool]("verbose", "v"),
+		Paw[string]("tag", "t"),
+	)
+	parser.AddCommand([]string{"docker", "run"}, []*Flag{Paw[bool]("detach", "d")})
+
+	args := []string{"docker", "run", "--rm", "-v", "--env=A=1", "-it", "-d", "--tag", "x", "image", "-p", "--", "sh", "-c"}
+	result, err := parser.Parse(args)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if !result.Bool("verbose") || !result.Bool("detach") || result.String("tag") != "x" {
+		t.Errorf("known flags not parsed: %v", result.Flags)
+	}
+	if want := []string{"--rm", "--env=A=1", "-it", "-p"}; !slices.Equal(result.Unknown, want) {
+		t.Errorf("Unknown = %v, want %v", result.Unknown, want)
+	}
+	if want := []string{"--rm", "--env=A=1", "-it", "image", "-p", "--", "sh", "-c"}; !slices.Equal(result.Passthrough, want) {
+		t.Errorf("Passthrough = %v, want %v", result.Passthrough, want)
+	}
+	if want := []string{"image", "sh", "-c"}; !slices.Equal(result.Positional, want) {
+		t.Errorf("Positional = %v, want %v", result.Positional, want)
+	}
+
+	t.Run("group with unknown is kept whole", func(t *testing.T) {
+		result, err := parser.Parse([]string{"-vx"})
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if result.Bool("verbose") {
+			t.Error("verbose should not be set from a passed-through group")
+		}
+		if !slices.Equal(result.Unknown, []string{"-vx"}) {
+			t.Errorf("Unknown = %v, want [-vx]", result.Unknown)
+		}
+	})
+
+	t.Run("invalid known value still fails", func(t *testing.T) {
+		parser := New()
+		parser.AllowUnknown = true
+		parser.AddFlags(Paw[int]("count"))
+		if _, err := parser.Parse([]string{"--count", "x"}); !errors.Is(err, ErrFlagValue) {
+			t.Errorf("Parse() error = %v, want ErrFlagValue", err)
 		}
 	})
 }
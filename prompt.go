@@ -0,0 +1,149 @@
+package paws
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Prompter asks for the values of required flags missing from the command line
+type Prompter struct {
+	In          io.Reader // Source of answers
+	Out         io.Writer // Destination of questions
+	Interactive bool      // Whether to ask at all, false keeps the required flag error
+
+	// ReadSecret reads an answer without echoing it, for secret flags.
+	// nil reads a line from In, unless In is a terminal that would echo it.
+	ReadSecret func() (string, error)
+}
+
+// NewPrompter returns a prompter asking on stderr and reading stdin,
+// interactive only when stdin is a terminal
+func NewPrompter() *Prompter {
+	return &Prompter{
+		In:          os.Stdin,
+		Out:         os.Stderr,
+		Interactive: isTerminal(os.Stdin),
+		ReadSecret:  func() (string, error) { return readPassword(os.Stdin) },
+	}
+}
+
+// PromptMissing asks for every required flag missing from result and stores
+// the answers, asking again until a value passes validation. Choices are
+// offered as a numbered menu, numeric ones as a list of values. Without an interactive prompter it returns
+// the error of ValidateRequired. Variables are bound again when result
+// was bound already.
+func (p *Parser) PromptMissing(result *ParseResult, pr *Prompter) error {
+	if pr == nil || !pr.Interactive {
+		return p.ValidateRequired(result)
+	}
+
+	c := result.index
+	if c == nil {
+		c = p.Compile()
+	}
+	for _, f := range p.missingRequired(result) {
+		if err := pr.ask(c, f, result); err != nil {
+			return err
+		}
+	}
+	return result.rebind()
+}
+
+// ask prompts for one flag until a valid value is given or input ends
+func (pr *Prompter) ask(c *Compiled, f *Flag, result *ParseResult) error {
+	label := f.Name
+	if f.HelpText != "" {
+		label += " (" + f.HelpText + ")"
+	}
+
+	for {
+		switch {
+		// Menu numbers would be ambiguous for numeric choices
+		case len(f.ChoicesOpt) > 0 && f.isNumeric():
+			fmt.Fprintf(pr.Out, "%s:\nEnter one of %s: ", label, strings.Join(f.ChoicesOpt, ", "))
+		case len(f.ChoicesOpt) > 0:
+			fmt.Fprintf(pr.Out, "%s:\n", label)
+			for i, o := range f.ChoicesOpt {
+				fmt.Fprintf(pr.Out, "  %d) %s", i+1, o)
+				if i < len(f.EnumOpts) && f.EnumOpts[i].Desc != "" {
+					fmt.Fprintf(pr.Out, " - %s", f.EnumOpts[i].Desc)
+				}
+				fmt.Fprintln(pr.Out)
+			}
+			fmt.Fprintf(pr.Out, "Choose 1-%d: ", len(f.ChoicesOpt))
+		default:
+			fmt.Fprintf(pr.Out, "%s: ", label)
+		}
+
+		answer, err := pr.read(f)
+		if errors.Is(err, io.EOF) {
+			return errorRequiredFlag(f.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			fmt.Fprintln(pr.Out, "A value is required.")
+			continue
+		}
+		if n, err := strconv.Atoi(answer); err == nil && !f.isNumeric() && n >= 1 && n <= len(f.ChoicesOpt) {
+			answer = f.ChoicesOpt[n-1]
+		}
+
+		if err := c.setFlag(f, answer, result, Origin{Source: SourcePrompt, Index: -1}); err != nil {
+			var pe *ParseError
+			switch {
+			case f.IsSecret:
+				fmt.Fprintln(pr.Out, "Invalid value.")
+			case errors.As(err, &pe) && pe.Cause != nil:
+				fmt.Fprintf(pr.Out, "Invalid value: %v.\n", pe.Cause)
+			default:
+				fmt.Fprintf(pr.Out, "Invalid value: %v.\n", err)
+			}
+			continue
+		}
+		return nil
+	}
+}
+
+// read reads one answer, without echo for secret flags
+func (pr *Prompter) read(f *Flag) (string, error) {
+	if f.IsSecret && pr.ReadSecret != nil {
+		s, err := pr.ReadSecret()
+		// The newline typed by the user was not echoed
+		fmt.Fprintln(pr.Out)
+		return s, err
+	}
+	if in, ok := pr.In.(*os.File); ok && f.IsSecret && isTerminal(in) {
+		return "", fmt.Errorf("refusing to echo secret flag %s", f.Name)
+	}
+	return readLine(pr.In)
+}
+
+// readLine reads up to a newline one byte at a time, so nothing past
+// the line is consumed from r. It returns io.EOF only when no byte was read.
+func readLine(r io.Reader) (string, error) {
+	var b strings.Builder
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				return strings.TrimSuffix(b.String(), "\r"), nil
+			}
+			b.WriteByte(buf[0])
+		}
+		if err == io.EOF && b.Len() > 0 {
+			return b.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
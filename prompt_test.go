@@ -0,0 +1,132 @@
+package paws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPromptMissing(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[string]("user").Required().Help("user name"))
+	parser.AddCommand([]string{"deploy"}, []*Flag{
+		Paw[string]("env").Choices("dev", "prod").Required(),
+		Paw[int]("replicas").Range(1, 5).Required(),
+		Paw[string]("token").Secret().Required(),
+	})
+	result, err := parser.Parse([]string{"deploy", "--user", "ann"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var out strings.Builder
+	secretCalls := 0
+	pr := &Prompter{
+		In:          strings.NewReader("staging\n2\n\n9\n3\n"),
+		Out:         &out,
+		Interactive: true,
+		ReadSecret: func() (string, error) {
+			secretCalls++
+			return "s3cret", nil
+		},
+	}
+
+	if err := parser.PromptMissing(result, pr); err != nil {
+		t.Fatalf("PromptMissing() error = %v", err)
+	}
+
+	for name, want := range map[string]string{"user": "ann", "env": "prod", "replicas": "3", "token": "s3cret"} {
+		if got := result.String(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if secretCalls != 1 {
+		t.Errorf("ReadSecret called %d times, want 1", secretCalls)
+	}
+	if got := result.Source("env").Source; got != SourcePrompt {
+		t.Errorf("Source(env) = %v, want prompt", got)
+	}
+
+	text := out.String()
+	for _, want := range []string{"env:\n  1) dev\n  2) prod\nChoose 1-2: ", "Invalid value: value 'staging' not in allowed choices", "A value is required.", "out of range"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("prompt output missing %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "s3cret") {
+		t.Error("prompt output shows the secret")
+	}
+}
+
+func TestPromptMissingNotInteractive(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[string]("user").Required())
+	result, _ := parser.Parse(nil)
+
+	pr := &Prompter{In: strings.NewReader("x\n"), Out: &strings.Builder{}}
+	if err := parser.PromptMissing(result, pr); !errors.Is(err, ErrRequiredFlag) {
+		t.Errorf("PromptMissing() error = %v, want ErrRequiredFlag", err)
+	}
+	if _, ok := result.Flags["user"]; ok {
+		t.Error("PromptMissing() should not read input when not interactive")
+	}
+}
+
+func TestPromptMissingEOF(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[string]("user").Required())
+	parser.AddCommand([]string{"deploy"}, []*Flag{Paw[string]("env").Choices("dev", "prod").Required()})
+	result, _ := parser.Parse([]string{"deploy"})
+
+	pr := &Prompter{In: strings.NewReader("ann"), Out: &strings.Builder{}, Interactive: true}
+	err := parser.PromptMissing(result, pr)
+
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Flag != "env" || !errors.Is(err, ErrRequiredFlag) {
+		t.Errorf("PromptMissing() error = %v, want required flag env", err)
+	}
+	if got := result.String("user"); got != "ann" {
+		t.Errorf("user = %q, want ann", got)
+	}
+}
+
+func TestPromptMissingBind(t *testing.T) {
+	var user string
+	parser := New()
+	BindVar(parser, &user, "user").Required()
+
+	result, err := parser.Parse(nil)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	pr := &Prompter{In: strings.NewReader("ann\n"), Out: &strings.Builder{}, Interactive: true}
+	if err := parser.PromptMissing(result, pr); err != nil {
+		t.Fatalf("PromptMissing() error = %v", err)
+	}
+	if user != "ann" {
+		t.Errorf("bound user = %q, want ann", user)
+	}
+}
+
+func TestPromptNumericChoices(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[int]("size").Choices("10", "20", "30").Required())
+	result, err := parser.Parse(nil)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var out strings.Builder
+	pr := &Prompter{In: strings.NewReader("2\n20\n"), Out: &out, Interactive: true}
+	if err := parser.PromptMissing(result, pr); err != nil {
+		t.Fatalf("PromptMissing() error = %v", err)
+	}
+	if got := result.String("size"); got != "20" {
+		t.Errorf("size = %q, want 20", got)
+	}
+
+	want := "size:\nEnter one of 10, 20, 30: Invalid value: value 2 not in allowed choices: [10 20 30].\nsize:\nEnter one of 10, 20, 30: "
+	if got := out.String(); got != want {
+		t.Errorf("prompt output = %q, want %q", got, want)
+	}
+}
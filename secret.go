@@ -0,0 +1,90 @@
+package paws
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// redacted replaces the values of secret flags in output meant for people and logs
+const redacted = "<redacted>"
+
+// fileFlagName returns the name of the companion flag reading the value of f from a file
+func (f *Flag) fileFlagName() string {
+	return f.Name + "-file"
+}
+
+// fileFlag returns a companion flag reading the value of f from a file
+func (f *Flag) fileFlag() *Flag {
+	return &Flag{
+		Name:     f.fileFlagName(),
+		Type:     FileType,
+		DefValue: "",
+		MetaVar:  "FILE",
+		FileMode: ReadMode,
+		fileOf:   f,
+	}
+}
+
+// setFromFile reads the value of the flag behind the companion f from the file
+// at path, or stdin for "-", and sets it with the origin of the companion
+func (c *Compiled) setFromFile(f *Flag, path string, result *ParseResult, o Origin) error {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else if path, err = f.expandPath(path); err == nil {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return errorFlagValue(f, path, err)
+	}
+
+	value := strings.TrimSuffix(string(data), "\n")
+	value = strings.TrimSuffix(value, "\r")
+	return c.setFlag(f.fileOf, value, result, o)
+}
+
+// hide records that the argument at index i holds the value of f from offset
+// on, so RedactedArgs can replace it when f is secret
+func (r *ParseResult) hide(f *Flag, i, offset int) {
+	if !f.IsSecret {
+		return
+	}
+	if r.hidden == nil {
+		r.hidden = make(map[int]int)
+	}
+	r.hidden[i] = offset
+}
+
+// RedactedArgs returns a copy of RawArgs with the values of secret flags
+// replaced, for logging
+func (r *ParseResult) RedactedArgs() []string {
+	args := make([]string, len(r.RawArgs))
+	copy(args, r.RawArgs)
+	for i, offset := range r.hidden {
+		args[i] = args[i][:offset] + redacted
+	}
+	return args
+}
+
+// shownValue returns value, or a placeholder when f is secret
+func shownValue(f *Flag, value string) string {
+	if f.IsSecret && value != "" {
+		return redacted
+	}
+	return value
+}
+
+// redactPath hides the path in a file system error when f is secret
+func redactPath(f *Flag, err error) error {
+	var pe *fs.PathError
+	if !f.IsSecret || !errors.As(err, &pe) {
+		return err
+	}
+	return &fs.PathError{Op: pe.Op, Path: redacted, Err: pe.Err}
+}
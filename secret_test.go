@@ -0,0 +1,205 @@
+package paws
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRedactedArgs(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[bool]("verbose", "v"), Paw[string]("token", "t").Secret(), Paw[string]("user", "u"))
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"separate", []string{"--token", "abc", "x"}, []string{"--token", "<redacted>", "x"}},
+		{"equals", []string{"--token=abc"}, []string{"--token=<redacted>"}},
+		{"short attached", []string{"-tabc"}, []string{"-t<redacted>"}},
+		{"short equals", []string{"-t=abc"}, []string{"-t=<redacted>"}},
+		{"short group", []string{"-vt", "abc"}, []string{"-vt", "<redacted>"}},
+		{"repeated", []string{"--token", "a", "-u", "ann", "--token=b"}, []string{"--token", "<redacted>", "-u", "ann", "--token=<redacted>"}},
+		{"after double dash", []string{"--", "--token", "abc"}, []string{"--", "--token", "abc"}},
+		{"not secret", []string{"--user", "abc"}, []string{"--user", "abc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := append([]string(nil), tt.args...)
+			r, err := parser.Parse(raw)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := r.RedactedArgs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RedactedArgs() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(r.RawArgs, tt.args) {
+				t.Errorf("RawArgs changed to %q", r.RawArgs)
+			}
+		})
+	}
+}
+
+func TestSecretParseError(t *testing.T) {
+	parser := New()
+	parser.IntLiterals = true
+	parser.AddFlags(
+		Paw[int]("pin").Secret(),
+		Paw[string]("key").Secret().Choices("k1", "k2"),
+		Paw[int]("code").Secret().Range(1000, 9999),
+		Paw[float64]("salt").Secret(),
+		Path("cert").Secret().Check(PathExists),
+	)
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"int", []string{"--pin", "hunter2"}, "invalid flag value: pin (invalid integer value: '<redacted>')"},
+		{"choice", []string{"--key", "k9"}, "invalid flag value: key (value '<redacted>' not in allowed choices: [k1 k2])"},
+		{"one letter choice", []string{"--key", "l"}, "invalid flag value: key (value '<redacted>' not in allowed choices: [k1 k2])"},
+		{"range", []string{"--code", "0x2a"}, "invalid flag value: code (value <redacted> out of range [1000, 9999])"},
+		{"one digit range", []string{"--code", "9"}, "invalid flag value: code (value <redacted> out of range [1000, 9999])"},
+		{"float", []string{"--salt", "x"}, "invalid flag value: salt (invalid float value: '<redacted>')"},
+		{"path", []string{"--cert", "/nonexistent/c"}, "invalid flag value: cert (path '<redacted>' does not exist)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.Parse(tt.args)
+
+			var pe *ParseError
+			if !errors.As(err, &pe) || !errors.Is(err, ErrFlagValue) {
+				t.Fatalf("Parse() error = %v, want ErrFlagValue", err)
+			}
+			if msg := err.Error(); msg != tt.want {
+				t.Errorf("Error() = %q, want %q", msg, tt.want)
+			}
+			if pe.Value != tt.args[1] {
+				t.Errorf("Value = %q, want the raw value for callers", pe.Value)
+			}
+		})
+	}
+}
+
+func TestSecretOutput(t *testing.T) {
+	parser := New()
+	parser.AddFlags(
+		Paw[bool]("verbose", "v"),
+		Paw[string]("token", "t").Secret().FromFile().Default("dev-token").Help("API token"),
+		Paw[string]("user", "u"),
+	)
+	r, err := parser.Parse([]string{"--token", "abc", "-u", "ann"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var help, explain, md, man strings.Builder
+	if err := parser.WriteHelp(&help, nil); err != nil {
+		t.Fatalf("WriteHelp() error = %v", err)
+	}
+	if err := r.Explain(&explain); err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	if err := parser.WriteMarkdown(&md, nil, MarkdownOptions{Name: "app"}); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	if err := parser.WriteMan(&man, nil, ManOptions{Name: "app"}); err != nil {
+		t.Fatalf("WriteMan() error = %v", err)
+	}
+
+	for name, text := range map[string]string{"help": help.String(), "explain": explain.String(), "markdown": md.String(), "man": man.String()} {
+		if strings.Contains(text, "dev-token") || strings.Contains(text, "abc") {
+			t.Errorf("%s shows a secret:\n%s", name, text)
+		}
+	}
+	if want := "API token (file: --token-file)"; !strings.Contains(help.String(), want) {
+		t.Errorf("help missing %q:\n%s", want, help.String())
+	}
+	if want := "token    <redacted>  cli (arg 0)"; !strings.Contains(explain.String(), want) {
+		t.Errorf("Explain() missing %q:\n%s", want, explain.String())
+	}
+
+	want := []string{"--token=<redacted>", "--user=ann"}
+	if got := r.Reconstruct(ReconstructOptions{Redact: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconstruct(Redact) = %q, want %q", got, want)
+	}
+}
+
+func TestSecretFromFile(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[string]("token").Secret().FromFile(), Paw[int]("pin").Secret())
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "token")
+	if err := os.WriteFile(file, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := parser.Parse([]string{"--token-file", file})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := r.String("token"); got != "s3cret" {
+		t.Errorf("token = %q, want s3cret", got)
+	}
+	if got := r.Source("token"); got.Source != SourceCLI || got.Index != 0 {
+		t.Errorf("Source(token) = %v, want cli (arg 0)", got)
+	}
+	if _, ok := r.Flags["token-file"]; ok {
+		t.Error("companion flag should not hold a value")
+	}
+
+	_, err = parser.Parse([]string{"--token-file", filepath.Join(dir, "missing")})
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Flag != "token-file" || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Parse() missing file error = %v", err)
+	}
+
+	// --pin has no companion
+	if _, err := parser.Parse([]string{"--pin-file", file}); !errors.Is(err, ErrUnknownFlag) {
+		t.Errorf("Parse(--pin-file) error = %v, want ErrUnknownFlag", err)
+	}
+}
+
+func TestSecretFromStdin(t *testing.T) {
+	stdin, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stdin.WriteString("piped\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stdin.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	old := os.Stdin
+	os.Stdin = stdin
+	t.Cleanup(func() { os.Stdin = old; stdin.Close() })
+
+	parser := New()
+	parser.AddFlags(Paw[string]("token").Secret().FromFile())
+	r, err := parser.Parse([]string{"--token-file", "-"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := r.String("token"); got != "piped" {
+		t.Errorf("token = %q, want piped", got)
+	}
+}
+
+func TestLintFileFlagClash(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[string]("key").Secret().FromFile(), Paw[string]("key-file"))
+
+	diags := parser.Lint()
+	if len(diags) != 1 || diags[0].Code != LintDuplicateName || diags[0].Flag != "key-file" {
+		t.Errorf("Lint() = %v, want duplicate key-file", diags)
+	}
+}
@@ -0,0 +1,309 @@
+package paws
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// Handler runs a command entered in a Shell
+type Handler func(r *ParseResult) error
+
+// builtins lists the commands every Shell understands
+var builtins = []struct{ name, help string }{
+	{"help", "Show commands, or the flags of a command"},
+	{"history", "Show the lines entered so far"},
+	{"exit", "Leave the shell"},
+	{"quit", "Leave the shell"},
+}
+
+// isBuiltin reports whether name is a command of every Shell
+func isBuiltin(name string) bool {
+	for _, b := range builtins {
+		if b.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Shell reads command lines repeatedly and dispatches them to handlers.
+// Lines are split like a POSIX shell and parsed with Parser.
+type Shell struct {
+	Parser  *Parser
+	In      io.Reader // Source of command lines
+	Out     io.Writer // Destination of prompts, help and errors
+	Prompt  string    // Prompt shown before each line, "> " when empty
+	History []string  // Lines entered so far, oldest first, with secret values redacted
+
+	handlers map[*CommandDef]Handler
+	compiled *Compiled
+}
+
+// NewShell creates a shell over the commands of p
+func NewShell(p *Parser, in io.Reader, out io.Writer) *Shell {
+	return &Shell{Parser: p, In: in, Out: out, handlers: make(map[*CommandDef]Handler)}
+}
+
+// Handle sets the handler run for cmd
+func (s *Shell) Handle(cmd *CommandDef, h Handler) *Shell {
+	if s.handlers == nil {
+		s.handlers = make(map[*CommandDef]Handler)
+	}
+	s.handlers[cmd] = h
+	return s
+}
+
+// Run reads and executes lines until "exit", "quit" or the end of input.
+// Errors of single lines are written to Out and do not stop the shell.
+// A line ending in a tab is not run, Run writes its completions instead.
+func (s *Shell) Run() error {
+	s.compiled = s.Parser.Compile()
+	defer func() { s.compiled = nil }()
+
+	prompt := s.Prompt
+	if prompt == "" {
+		prompt = "> "
+	}
+
+	for {
+		fmt.Fprint(s.Out, prompt)
+		line, err := s.readLine()
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(s.Out)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if partial, ok := strings.CutSuffix(line, "\t"); ok {
+			s.writeCompletions(partial)
+			continue
+		}
+
+		stop, err := s.Exec(line)
+		if err != nil {
+			fmt.Fprintf(s.Out, "error: %v\n", err)
+		}
+		if stop {
+			return nil
+		}
+	}
+}
+
+// readLine reads a line, continuing on the next ones after a trailing
+// backslash or inside an unterminated quote. A line asking for completion
+// with a trailing tab is returned as is.
+func (s *Shell) readLine() (string, error) {
+	line, err := readLine(s.In)
+	if err != nil || strings.HasSuffix(line, "\t") {
+		return line, err
+	}
+
+	for {
+		_, _, err := splitWords(line)
+		if !errors.Is(err, errUnterminatedQuote) && !errors.Is(err, errTrailingEscape) {
+			return line, nil
+		}
+		fmt.Fprint(s.Out, "... ")
+		next, err := readLine(s.In)
+		if errors.Is(err, io.EOF) {
+			return line, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		line += "\n" + next
+	}
+}
+
+// Exec runs a single line and reports whether the shell should stop.
+// The line may span several lines joined by continuations.
+func (s *Shell) Exec(line string) (stop bool, err error) {
+	args, err := Split(line)
+	if err != nil {
+		return false, err
+	}
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	if isBuiltin(args[0]) {
+		s.History = append(s.History, line)
+	}
+	switch args[0] {
+	case "exit", "quit":
+		return true, nil
+	case "history":
+		for i, h := range s.History {
+			fmt.Fprintf(s.Out, "%5d  %s\n", i+1, h)
+		}
+		return false, nil
+	case "help":
+		return false, s.help(args[1:])
+	}
+
+	// Lines that do not parse are left out, their secret values are unknown
+	c := s.compile()
+	r, err := c.Parse(args)
+	if err != nil {
+		return false, err
+	}
+	if len(r.hidden) > 0 {
+		line = Join(r.RedactedArgs())
+	}
+	s.History = append(s.History, line)
+
+	if r.Command == nil {
+		return false, fmt.Errorf("unknown command %q, type help for a list", args[0])
+	}
+	h, ok := s.handlers[r.Command]
+	if !ok {
+		return false, fmt.Errorf("command %q has no handler", r.Command.Name())
+	}
+	if err := s.Parser.ValidateRequired(r); err != nil {
+		return false, err
+	}
+	if err := r.Bind(); err != nil {
+		return false, err
+	}
+	return false, h(r)
+}
+
+// Complete returns the candidates for the last word of line: commands,
+// flags when the word starts with "-", or choices after a flag taking one
+func (s *Shell) Complete(line string) []string {
+	words, open, _ := splitWords(line)
+	prefix := ""
+	if open {
+		prefix = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	c := s.compile()
+
+	// Commands given before, skipping "help"
+	node, cmd := c.trie, (*CommandDef)(nil)
+	path := words
+	if len(path) > 0 && path[0] == "help" {
+		path = path[1:]
+	}
+	matched := 0
+	for _, w := range path {
+		child, ok := node.children[w]
+		if !ok {
+			break
+		}
+		matched++
+		node = child
+		if node.cmd != nil {
+			cmd = node.cmd
+		}
+	}
+
+	var candidates []string
+	add := func(s string) {
+		if strings.HasPrefix(s, prefix) && !slices.Contains(candidates, s) {
+			candidates = append(candidates, s)
+		}
+	}
+
+	if len(words) > 0 {
+		if last := words[len(words)-1]; strings.HasPrefix(last, "-") && !strings.Contains(last, "=") {
+			f := c.findFlag(strings.TrimLeft(last, "-"), cmd)
+			if f != nil && f.Type != BoolType && !f.IsOptional {
+				for _, o := range f.ChoicesOpt {
+					add(o)
+				}
+				return candidates
+			}
+		}
+	}
+
+	if strings.HasPrefix(prefix, "-") {
+		for _, f := range s.Parser.scopeFlags(cmd) {
+			for _, n := range append([]string{f.Name}, f.Aliases...) {
+				add(dashed(n))
+			}
+		}
+		slices.Sort(candidates)
+		return candidates
+	}
+
+	if len(words) == 0 {
+		for _, b := range builtins {
+			add(b.name)
+		}
+	}
+	// Subcommands only follow command words
+	if matched == len(path) {
+		for _, w := range node.words {
+			add(w)
+		}
+	}
+	slices.Sort(candidates)
+	return candidates
+}
+
+// writeCompletions writes the candidates Complete returns for line
+func (s *Shell) writeCompletions(line string) {
+	candidates := s.Complete(line)
+	if len(candidates) == 0 {
+		fmt.Fprintln(s.Out, "no completions")
+		return
+	}
+	fmt.Fprintln(s.Out, strings.Join(candidates, "  "))
+}
+
+// help writes the command list, or the help of the command in args
+func (s *Shell) help(args []string) error {
+	c := s.compile()
+	if len(args) == 0 {
+		tw := tabwriter.NewWriter(s.Out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "Commands:")
+		for _, cmd := range s.Parser.Commands {
+			fmt.Fprintf(tw, "  %s\t%s\n", cmd.Name(), summary(cmd.HelpText))
+		}
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "Shell Commands:")
+		for _, b := range builtins {
+			fmt.Fprintf(tw, "  %s\t%s\n", b.name, b.help)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		if len(s.Parser.Flags) > 0 {
+			fmt.Fprintln(s.Out)
+			return s.Parser.WriteHelp(s.Out, nil)
+		}
+		return nil
+	}
+
+	cmd, n, err := c.findCommand(args)
+	if err != nil {
+		return err
+	}
+	if cmd == nil || n != len(args) {
+		return fmt.Errorf("unknown command %q", strings.Join(args, " "))
+	}
+
+	fmt.Fprintf(s.Out, "Usage: %s\n", s.Parser.usageLine("", cmd))
+	if cmd.HelpText != "" {
+		fmt.Fprintf(s.Out, "\n%s\n", strings.TrimSpace(cmd.HelpText))
+	}
+	if len(cmd.Flags) > 0 || len(s.Parser.Flags) > 0 {
+		fmt.Fprintln(s.Out)
+	}
+	return s.Parser.WriteHelp(s.Out, cmd)
+}
+
+// compile returns the parser compiled by Run, or compiles it for single calls
+func (s *Shell) compile() *Compiled {
+	if s.compiled != nil {
+		return s.compiled
+	}
+	return s.Parser.Compile()
+}
@@ -0,0 +1,175 @@
+package paws
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShellRun(t *testing.T) {
+	parser := New()
+	add := parser.AddCommand([]string{"user", "add"}, []*Flag{Paw[string]("role").Required()})
+	del := parser.AddCommand([]string{"user", "delete"}, nil)
+	parser.AddCommand([]string{"status"}, nil)
+
+	var calls []string
+	var out strings.Builder
+	input := strings.Join([]string{
+		`user add --role admin "Ann Lee"`,
+		``,
+		`user delete 'Bob'`,
+		`status`,
+		`nope`,
+		`user add`,
+		`history`,
+		`exit`,
+		`user delete never`,
+	}, "\n")
+
+	sh := NewShell(parser, strings.NewReader(input), &out)
+	sh.Prompt = "$ "
+	sh.Handle(add, func(r *ParseResult) error {
+		calls = append(calls, "add "+r.String("role")+" "+r.Positional[0])
+		return nil
+	})
+	sh.Handle(del, func(r *ParseResult) error {
+		calls = append(calls, "delete "+r.Positional[0])
+		return errors.New("not allowed")
+	})
+
+	if err := sh.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"add admin Ann Lee", "delete Bob"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("handler calls = %q, want %q", calls, want)
+	}
+
+	text := out.String()
+	for _, w := range []string{
+		"error: not allowed",
+		`error: command "status" has no handler`,
+		`error: unknown command "nope"`,
+		"error: required flag missing: role",
+		"    1  user add --role admin \"Ann Lee\"\n",
+		"    6  history\n",
+	} {
+		if !strings.Contains(text, w) {
+			t.Errorf("output missing %q in:\n%s", w, text)
+		}
+	}
+	if len(sh.History) != 7 {
+		t.Errorf("History has %d lines, want 7", len(sh.History))
+	}
+}
+
+func TestShellHelp(t *testing.T) {
+	parser := New()
+	parser.AddCommand([]string{"user", "add"}, []*Flag{
+		Paw[string]("role").Choices("admin", "guest").Required(),
+		Paw[bool]("force", "f"),
+	}).Help("Add a user")
+	parser.AddCommand([]string{"user", "delete"}, nil).Help("Delete a user")
+	var out strings.Builder
+	sh := NewShell(parser, strings.NewReader("help\nhelp user add\nhelp user nope\n"), &out)
+
+	if err := sh.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	text := out.String()
+	for _, w := range []string{
+		"Commands:\n  user add     Add a user\n",
+		"Shell Commands:",
+		"  exit",
+		"Usage: user add [options] --role VALUE\n\nAdd a user\n",
+		"(one of: admin, guest; required)",
+		`error: unknown command "user nope"`,
+	} {
+		if !strings.Contains(text, w) {
+			t.Errorf("output missing %q in:\n%s", w, text)
+		}
+	}
+}
+
+func TestShellComplete(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[bool]("verbose", "v"))
+	parser.AddCommand([]string{"user", "add"}, []*Flag{
+		Paw[string]("role").Choices("admin", "guest").Required(),
+		Paw[bool]("force", "f"),
+	})
+	parser.AddCommand([]string{"user", "delete"}, nil)
+	parser.AddCommand([]string{"status"}, nil)
+	sh := NewShell(parser, nil, nil)
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", []string{"exit", "help", "history", "quit", "status", "user"}},
+		{"h", []string{"help", "history"}},
+		{"user ", []string{"add", "delete"}},
+		{"user d", []string{"delete"}},
+		{"user add --", []string{"--force", "--role", "--verbose"}},
+		{"user add -", []string{"--force", "--role", "--verbose", "-f", "-v"}},
+		{"user add --role ", []string{"admin", "guest"}},
+		{"user add --role g", []string{"guest"}},
+		{"help user ", []string{"add", "delete"}},
+		{"user add x ", nil},
+		{`user "ad`, []string{"add"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := sh.Complete(tt.line); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Complete(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellRunComplete(t *testing.T) {
+	parser := New()
+	add := parser.AddCommand([]string{"user", "add"}, []*Flag{Paw[string]("role").Choices("admin", "guest")})
+	parser.AddCommand([]string{"user", "delete"}, nil)
+	var out strings.Builder
+	sh := NewShell(parser, strings.NewReader("user \t\nuser add --role \t\nuser add x \t\n"), &out)
+	sh.Handle(add, func(r *ParseResult) error {
+		t.Error("handler run for a completion request")
+		return nil
+	})
+
+	if err := sh.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := "> add  delete\n> admin  guest\n> no completions\n> \n"
+	if got := out.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if len(sh.History) != 0 {
+		t.Errorf("History = %q, want completions left out", sh.History)
+	}
+}
+
+func TestShellHistorySecret(t *testing.T) {
+	parser := New()
+	login := parser.AddCommand([]string{"login"}, []*Flag{Paw[string]("token", "t").Secret(), Paw[string]("user")})
+
+	var out strings.Builder
+	sh := NewShell(parser, strings.NewReader("login --token hunter2 --user ann\nlogin -thunter2\nlogin --user \"ann lee\"\nlogin --token\nhistory\n"), &out)
+	sh.Handle(login, func(r *ParseResult) error { return nil })
+
+	if err := sh.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := []string{"login --token '<redacted>' --user ann", "login '-t<redacted>'", `login --user "ann lee"`, "history"}
+	if !reflect.DeepEqual(sh.History, want) {
+		t.Errorf("History = %q, want %q", sh.History, want)
+	}
+	if strings.Contains(out.String(), "hunter2") {
+		t.Errorf("output shows the secret:\n%s", out.String())
+	}
+}
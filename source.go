@@ -0,0 +1,159 @@
+package paws
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+)
+
+// Source tells where a flag value came from
+type Source int
+
+const (
+	SourceDefault Source = iota // Flag default, nothing was given
+	SourceCLI                   // Command line argument
+	SourceEnv                   // Environment variable
+	SourceConfig                // Configuration file
+	SourcePrompt                // Answer to an interactive prompt
+)
+
+// String returns the source name
+func (s Source) String() string {
+	switch s {
+	case SourceCLI:
+		return "cli"
+	case SourceEnv:
+		return "env"
+	case SourceConfig:
+		return "config"
+	case SourcePrompt:
+		return "prompt"
+	}
+	return "default"
+}
+
+// Origin records where a flag value was set
+type Origin struct {
+	Source Source // Kind of source
+	Name   string // Environment variable or config file, if any
+	Index  int    // Index in RawArgs of the flag for cli values, -1 otherwise
+}
+
+// String returns a short description, e.g. "cli (arg 2)" or "env ($TOKEN)"
+func (o Origin) String() string {
+	switch o.Source {
+	case SourceCLI:
+		return fmt.Sprintf("cli (arg %d)", o.Index)
+	case SourceEnv:
+		return fmt.Sprintf("env ($%s)", o.Name)
+	case SourceConfig:
+		return fmt.Sprintf("config (%s)", o.Name)
+	case SourcePrompt:
+		return "prompt"
+	}
+	return "default"
+}
+
+// cliAt returns the origin of a value given by the argument at index i
+func cliAt(i int) Origin {
+	return Origin{Source: SourceCLI, Index: i}
+}
+
+// set stores a flag value along with its origin
+func (r *ParseResult) set(f *Flag, value string, o Origin) {
+	r.Flags[f.Name] = value
+	if r.origins == nil {
+		r.origins = make(map[string]Origin)
+	}
+	r.origins[f.Name] = o
+}
+
+// Changed reports whether the flag was given on the command line,
+// the environment or a config file rather than left at its default
+func (r *ParseResult) Changed(n string) bool {
+	return r.Source(n).Source != SourceDefault
+}
+
+// Source returns where the value of the flag came from
+func (r *ParseResult) Source(n string) Origin {
+	name := n
+	if f := r.findFlag(n); f != nil {
+		name = f.Name
+	}
+
+	if o, ok := r.origins[name]; ok {
+		return o
+	}
+	if _, ok := r.Flags[name]; ok {
+		return Origin{Source: SourceCLI, Index: -1}
+	}
+	return Origin{Source: SourceDefault, Index: -1}
+}
+
+// ApplyConfig sets flag values read from a config file.
+// Values already given on the command line or the environment are kept.
+func (r *ParseResult) ApplyConfig(values map[string]string, file string) error {
+	if r.index == nil {
+		return fmt.Errorf("%w: result was not produced by a parser", ErrParse)
+	}
+
+	for n, v := range values {
+		f := r.findFlag(n)
+		if f == nil {
+			return errorUnknownFlag(n)
+		}
+		if src := r.Source(f.Name).Source; src == SourceCLI || src == SourceEnv {
+			continue
+		}
+		if err := r.index.setFlag(f, v, r, Origin{Source: SourceConfig, Name: file, Index: -1}); err != nil {
+			return err
+		}
+	}
+	return r.rebind()
+}
+
+// applyEnv fills flags not given on the command line from their environment variables
+func (c *Compiled) applyEnv(cmd *CommandDef, result *ParseResult) error {
+	apply := func(flags []*Flag) error {
+		for _, f := range flags {
+			if _, ok := result.Flags[f.Name]; ok {
+				continue
+			}
+			for _, env := range f.EnvVars {
+				v, ok := os.LookupEnv(env)
+				if !ok {
+					continue
+				}
+				if err := c.setFlag(f, v, result, Origin{Source: SourceEnv, Name: env, Index: -1}); err != nil {
+					return err
+				}
+				break
+			}
+		}
+		return nil
+	}
+
+	if err := apply(c.p.Flags); err != nil {
+		return err
+	}
+	if cmd != nil {
+		return apply(cmd.Flags)
+	}
+	return nil
+}
+
+// Explain writes the effective value and origin of every flag.
+// Values of secret flags are redacted.
+func (r *ParseResult) Explain(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	for _, f := range r.scopeFlags() {
+		value, ok := r.Flags[f.Name]
+		if !ok && f.DefValue != nil {
+			value = fmt.Sprint(f.DefValue)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", f.Name, shownValue(f, value), r.Source(f.Name))
+	}
+	return tw.Flush()
+}
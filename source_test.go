@@ -0,0 +1,113 @@
+package paws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSource(t *testing.T) {
+	t.Setenv("APP_PORT", "9090")
+	t.Setenv("APP_TOKEN", "secret")
+
+	parser := New()
+	parser.AddFlags(
+		Paw[int]("port", "p").Default(8080).Env("APP_PORT"),
+		Paw[string]("token").Env("APP_TOKEN_OLD", "APP_TOKEN"),
+		Paw[string]("host").Default("localhost"),
+		Paw[bool]("verbose", "v"),
+		Paw[string]("mode"),
+	)
+
+	result, err := parser.Parse([]string{"arg", "-v", "--mode", "fast"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		want    Origin
+		changed bool
+	}{
+		{"verbose", Origin{Source: SourceCLI, Index: 1}, true},
+		{"v", Origin{Source: SourceCLI, Index: 1}, true},
+		{"mode", Origin{Source: SourceCLI, Index: 2}, true},
+		{"port", Origin{Source: SourceEnv, Name: "APP_PORT", Index: -1}, true},
+		{"token", Origin{Source: SourceEnv, Name: "APP_TOKEN", Index: -1}, true},
+		{"host", Origin{Source: SourceDefault, Index: -1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := result.Source(tt.name); got != tt.want {
+				t.Errorf("Source() = %+v, want %+v", got, tt.want)
+			}
+			if got := result.Changed(tt.name); got != tt.changed {
+				t.Errorf("Changed() = %v, want %v", got, tt.changed)
+			}
+		})
+	}
+
+	if result.Int("port") != 9090 {
+		t.Errorf("port = %d, want 9090", result.Int("port"))
+	}
+
+	t.Run("cli wins over env", func(t *testing.T) {
+		result, err := parser.Parse([]string{"--port", "1"})
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if result.Int("port") != 1 || result.Source("port").Source != SourceCLI {
+			t.Errorf("port = %d from %v, want 1 from cli", result.Int("port"), result.Source("port"))
+		}
+	})
+
+	t.Run("invalid env value", func(t *testing.T) {
+		t.Setenv("APP_PORT", "x")
+		if _, err := parser.Parse(nil); !errors.Is(err, ErrFlagValue) {
+			t.Errorf("Parse() error = %v, want ErrFlagValue", err)
+		}
+	})
+}
+
+func TestApplyConfig(t *testing.T) {
+	t.Setenv("APP_PORT", "9090")
+
+	parser := New()
+	parser.AddFlags(
+		Paw[int]("port").Env("APP_PORT"),
+		Paw[string]("host"),
+		Paw[string]("mode"),
+	)
+
+	result, err := parser.Parse([]string{"--mode", "slow"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	err = result.ApplyConfig(map[string]string{"port": "1", "host": "example.com", "mode": "fast"}, "app.toml")
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+
+	if result.Int("port") != 9090 || result.String("mode") != "slow" {
+		t.Errorf("config should not override env or cli: %v", result.Flags)
+	}
+	if got := result.Source("host"); got.Source != SourceConfig || got.Name != "app.toml" {
+		t.Errorf("Source(host) = %+v, want config app.toml", got)
+	}
+
+	if err := result.ApplyConfig(map[string]string{"nope": "1"}, "app.toml"); !errors.Is(err, ErrUnknownFlag) {
+		t.Errorf("ApplyConfig() error = %v, want ErrUnknownFlag", err)
+	}
+
+	var b strings.Builder
+	if err := result.Explain(&b); err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	for _, want := range []string{"port  9090", "env ($APP_PORT)", "config (app.toml)", "cli (arg 0)"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Explain() missing %q:\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,523 @@
+package paws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SpecVersion is the version of the JSON spec schema
+const SpecVersion = 1
+
+// Spec is the JSON description of a parser, its global flags and commands
+type Spec struct {
+	Version        int           `json:"version"`
+	AllowAbbrev    bool          `json:"allow_abbrev,omitempty"`
+	AllowUnknown   bool          `json:"allow_unknown,omitempty"`
+	Strict         bool          `json:"strict,omitempty"`
+	IntLiterals    bool          `json:"int_literals,omitempty"`
+	Interspersal   string        `json:"interspersal,omitempty"` // "interspersed" or "non-interspersed"
+	PosixlyCorrect bool          `json:"posixly_correct,omitempty"`
+	Flags          []FlagSpec    `json:"flags,omitempty"`
+	Commands       []CommandSpec `json:"commands,omitempty"`
+}
+
+// CommandSpec is the JSON description of a command
+type CommandSpec struct {
+	Path         []string   `json:"path"`
+	Help         string     `json:"help,omitempty"`
+	Interspersal string     `json:"interspersal,omitempty"`
+	Flags        []FlagSpec `json:"flags,omitempty"`
+	Args         []ArgSpec  `json:"args,omitempty"`
+}
+
+// ArgSpec is the JSON description of a positional argument
+type ArgSpec struct {
+	Name     string `json:"name"`
+	Help     string `json:"help,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	Variadic bool   `json:"variadic,omitempty"`
+}
+
+// FlagSpec is the JSON description of a flag.
+// Default holds a JSON value matching the type, e.g. 5, true or "fast".
+type FlagSpec struct {
+	Name        string          `json:"name"`
+	Aliases     []string        `json:"aliases,omitempty"`
+	Type        string          `json:"type"` // Type name as shown in help, e.g. "int", "uint8", "path"
+	Default     json.RawMessage `json:"default,omitempty"`
+	Required    bool            `json:"required,omitempty"`
+	Help        string          `json:"help,omitempty"`
+	Meta        string          `json:"meta,omitempty"`
+	Choices     []string        `json:"choices,omitempty"`
+	Enum        []EnumOption    `json:"enum,omitempty"`
+	IgnoreCase  bool            `json:"ignore_case,omitempty"`
+	Min         *BoundSpec      `json:"min,omitempty"`
+	Max         *BoundSpec      `json:"max,omitempty"`
+	Env         []string        `json:"env,omitempty"`
+	Optional    bool            `json:"optional,omitempty"`
+	Implied     string          `json:"implied,omitempty"` // Value implied when Optional and given without one
+	AllowHyphen bool            `json:"allow_hyphen,omitempty"`
+	IntLiterals bool            `json:"int_literals,omitempty"`
+	Check       []string        `json:"check,omitempty"` // "exists", "not-exists", "file", "dir", "readable", "writable"
+	Absolute    bool            `json:"absolute,omitempty"`
+	Mode        string          `json:"mode,omitempty"` // "read" or "write", file flags only
+	Secret      bool            `json:"secret,omitempty"`
+	FromFile    bool            `json:"from_file,omitempty"` // Accept --<name>-file
+}
+
+// BoundSpec is the JSON description of a range bound
+type BoundSpec struct {
+	Value     float64 `json:"value"`
+	Exclusive bool    `json:"exclusive,omitempty"`
+}
+
+var (
+	interspersalNames = map[Interspersal]string{Interspersed: "interspersed", NonInterspersed: "non-interspersed"}
+	pathCheckNames    = []string{"exists", "not-exists", "file", "dir", "readable", "writable"}
+	fileModeNames     = map[FileMode]string{ReadMode: "read", WriteMode: "write"}
+)
+
+// Export returns the JSON spec of the parser.
+// Enum flags keep their tokens only, their Go values cannot be described.
+func Export(p *Parser) ([]byte, error) {
+	s, err := NewSpec(p)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// FromSpec reads a JSON spec and builds the parser it describes.
+// Enum flags hold their canonical token as Go value, read with GetEnum[string].
+// Use ReadSpec and Spec.Warnings to also see the lint warnings.
+func FromSpec(r io.Reader) (*Parser, error) {
+	s, err := ReadSpec(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Parser()
+}
+
+// NewSpec describes the parser as a spec
+func NewSpec(p *Parser) (*Spec, error) {
+	s := &Spec{
+		Version:        SpecVersion,
+		AllowAbbrev:    p.AllowAbbrev,
+		AllowUnknown:   p.AllowUnknown,
+		Strict:         p.Strict,
+		IntLiterals:    p.IntLiterals,
+		Interspersal:   interspersalNames[p.Interspersal],
+		PosixlyCorrect: p.PosixlyCorrect,
+	}
+
+	var err error
+	if s.Flags, err = flagSpecs("$.flags", p.Flags); err != nil {
+		return nil, err
+	}
+
+	for i, cmd := range p.Commands {
+		cs := CommandSpec{
+			Path:         cmd.Path,
+			Help:         cmd.HelpText,
+			Interspersal: interspersalNames[cmd.Interspersal],
+		}
+		if cs.Flags, err = flagSpecs(fmt.Sprintf("$.commands[%d].flags", i), cmd.Flags); err != nil {
+			return nil, err
+		}
+		for _, a := range cmd.Positionals {
+			cs.Args = append(cs.Args, ArgSpec{Name: a.Name, Help: a.HelpText, Optional: a.IsOptional, Variadic: a.IsVariadic})
+		}
+		s.Commands = append(s.Commands, cs)
+	}
+	return s, nil
+}
+
+// ReadSpec decodes a JSON spec, rejecting unknown fields
+func ReadSpec(r io.Reader) (*Spec, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var s Spec
+	if err := dec.Decode(&s); err != nil {
+		var te *json.UnmarshalTypeError
+		if errors.As(err, &te) && te.Field != "" {
+			return nil, &SpecError{Path: jsonPath(te.Field), Err: fmt.Errorf("expected %s, got %s", te.Type, te.Value)}
+		}
+		return nil, &SpecError{Path: "$", Err: err}
+	}
+	return &s, nil
+}
+
+// Parser builds the parser described by the spec and validates it.
+// Only lint errors make it fail, Warnings returns the rest.
+func (s *Spec) Parser() (*Parser, error) {
+	if s.Version != SpecVersion {
+		return nil, &SpecError{Path: "$.version", Err: fmt.Errorf("unsupported version %d, want %d", s.Version, SpecVersion)}
+	}
+
+	p := New()
+	p.AllowAbbrev = s.AllowAbbrev
+	p.AllowUnknown = s.AllowUnknown
+	p.IntLiterals = s.IntLiterals
+	p.PosixlyCorrect = s.PosixlyCorrect
+
+	var err error
+	if p.Interspersal, err = parseInterspersal("$.interspersal", s.Interspersal); err != nil {
+		return nil, err
+	}
+	if p.Flags, err = specFlags("$.flags", s.Flags); err != nil {
+		return nil, err
+	}
+
+	for i, cs := range s.Commands {
+		path := fmt.Sprintf("$.commands[%d]", i)
+		cmd := &CommandDef{Path: cs.Path, HelpText: cs.Help}
+		if cmd.Interspersal, err = parseInterspersal(path+".interspersal", cs.Interspersal); err != nil {
+			return nil, err
+		}
+		if cmd.Flags, err = specFlags(path+".flags", cs.Flags); err != nil {
+			return nil, err
+		}
+		for j, a := range cs.Args {
+			if a.Name == "" {
+				return nil, &SpecError{Path: fmt.Sprintf("%s.args[%d].name", path, j), Err: errors.New("argument has no name")}
+			}
+			cmd.Positionals = append(cmd.Positionals, &Arg{Name: a.Name, HelpText: a.Help, IsOptional: a.Optional, IsVariadic: a.Variadic})
+		}
+		p.Commands = append(p.Commands, cmd)
+	}
+
+	if errs := s.lintErrors(p, SeverityError); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	p.Strict = s.Strict
+	return p, nil
+}
+
+// Warnings returns the lint warnings of a valid spec, such as command flags
+// hidden by global flags. They do not stop Parser from building the parser.
+func (s *Spec) Warnings() ([]*SpecError, error) {
+	p, err := s.Parser()
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []*SpecError
+	for _, err := range s.lintErrors(p, SeverityWarning) {
+		warnings = append(warnings, err.(*SpecError))
+	}
+	return warnings, nil
+}
+
+// lintErrors returns the lint diagnostics of p with the given severity,
+// located in the spec p was built from
+func (s *Spec) lintErrors(p *Parser, sev Severity) []error {
+	var errs []error
+	for _, d := range p.Lint() {
+		if d.Code.Severity() == sev {
+			errs = append(errs, &SpecError{Path: s.locate(d), Err: errors.New(d.Message)})
+		}
+	}
+	return errs
+}
+
+// locate returns the JSON path of the command or flag a diagnostic refers to.
+// The parser holds the commands and flags in the order of the spec.
+func (s *Spec) locate(d Diagnostic) string {
+	path := "$"
+	if d.cmdIndex >= 0 {
+		path = fmt.Sprintf("$.commands[%d]", d.cmdIndex)
+		if d.flagIndex < 0 {
+			return path + ".path"
+		}
+	}
+	if d.flagIndex < 0 {
+		return path
+	}
+	return fmt.Sprintf("%s.flags[%d]", path, d.flagIndex)
+}
+
+// flagSpecs describes a list of flags, path is the JSON path of the list
+func flagSpecs(path string, flags []*Flag) ([]FlagSpec, error) {
+	var specs []FlagSpec
+	for i, f := range flags {
+		fs := FlagSpec{
+			Name:        f.Name,
+			Aliases:     f.Aliases,
+			Type:        f.Type.String(),
+			Required:    f.IsRequired,
+			Help:        f.HelpText,
+			Meta:        f.MetaVar,
+			Enum:        f.EnumOpts,
+			IgnoreCase:  f.FoldCase,
+			Env:         f.EnvVars,
+			Optional:    f.IsOptional,
+			Implied:     f.NoOptDefault,
+			AllowHyphen: f.AllowHyphen,
+			IntLiterals: f.IntLiterals,
+			Absolute:    f.AbsPath,
+			Secret:      f.IsSecret,
+			FromFile:    f.FileFlag,
+		}
+		if f.Type != EnumType {
+			fs.Choices = f.ChoicesOpt
+		}
+		if f.Min != nil {
+			fs.Min = &BoundSpec{Value: f.Min.Value, Exclusive: f.Min.Exclusive}
+		}
+		if f.Max != nil {
+			fs.Max = &BoundSpec{Value: f.Max.Value, Exclusive: f.Max.Exclusive}
+		}
+		for bit, name := range pathCheckNames {
+			if f.PathCheck&(1<<bit) != 0 {
+				fs.Check = append(fs.Check, name)
+			}
+		}
+		if f.Type == FileType {
+			fs.Mode = fileModeNames[f.FileMode]
+		}
+
+		def, err := defaultJSON(f)
+		if err != nil {
+			return nil, &SpecError{Path: fmt.Sprintf("%s[%d].default", path, i), Err: err}
+		}
+		fs.Default = def
+
+		specs = append(specs, fs)
+	}
+	return specs, nil
+}
+
+// defaultJSON encodes a default set on the flag as a JSON value of the flag type
+func defaultJSON(f *Flag) (json.RawMessage, error) {
+	if f.DefValue == nil {
+		return nil, nil
+	}
+	value, ok := defaultValue(f)
+	if !ok {
+		return nil, fmt.Errorf("default %v (%T) does not match the flag type", f.DefValue, f.DefValue)
+	}
+	if !f.hasDefault && reflect.ValueOf(f.DefValue).IsZero() {
+		return nil, nil
+	}
+
+	if f.Type == BoolType || f.isNumeric() {
+		if !json.Valid([]byte(value)) {
+			return nil, fmt.Errorf("default %s cannot be written as JSON", value)
+		}
+		return json.RawMessage(value), nil
+	}
+	return json.Marshal(value)
+}
+
+// specFlags builds a list of flags, path is the JSON path of the list
+func specFlags(path string, specs []FlagSpec) ([]*Flag, error) {
+	var flags []*Flag
+	for i, fs := range specs {
+		f, err := specFlag(fs)
+		if err != nil {
+			var se *SpecError
+			if errors.As(err, &se) {
+				se.Path = fmt.Sprintf("%s[%d].%s", path, i, se.Path)
+				return nil, se
+			}
+			return nil, &SpecError{Path: fmt.Sprintf("%s[%d]", path, i), Err: err}
+		}
+		flags = append(flags, f)
+	}
+	return flags, nil
+}
+
+// specFlag builds one flag, errors carry the offending field as path
+func specFlag(fs FlagSpec) (*Flag, error) {
+	fieldErr := func(field, format string, args ...any) error {
+		return &SpecError{Path: field, Err: fmt.Errorf(format, args...)}
+	}
+
+	t, ok := parseFlagType(fs.Type)
+	if !ok {
+		return nil, fieldErr("type", "unknown type %q", fs.Type)
+	}
+
+	f := &Flag{Name: fs.Name, Aliases: fs.Aliases, Type: t}
+	if t == EnumType {
+		values := make([]EnumValue[string], 0, len(fs.Enum))
+		for _, o := range fs.Enum {
+			values = append(values, EnumValue[string]{Token: o.Token, Aliases: o.Aliases, Value: o.Token, Desc: o.Desc})
+		}
+		if err := catch(func() { f = Enum(fs.Name, values, fs.Aliases...) }); err != nil {
+			return nil, fieldErr("enum", "%v", err)
+		}
+	} else if len(fs.Enum) > 0 {
+		return nil, fieldErr("enum", "only valid for enum flags")
+	}
+
+	f.IsRequired = fs.Required
+	f.HelpText = fs.Help
+	f.MetaVar = fs.Meta
+	f.EnvVars = fs.Env
+	f.IsSecret = fs.Secret
+
+	def, err := specDefault(t, fs.Default)
+	if err != nil {
+		return nil, fieldErr("default", "%v", err)
+	}
+	f.DefValue = def
+	f.hasDefault = len(fs.Default) > 0
+
+	// Builder methods panic on misuse, report those as schema errors
+	steps := []struct {
+		field string
+		set   bool
+		apply func()
+	}{
+		{"choices", len(fs.Choices) > 0, func() { f.Choices(fs.Choices...) }},
+		{"ignore_case", fs.IgnoreCase, func() { f.IgnoreCase() }},
+		{"min", fs.Min != nil, func() { f.AtLeast(fs.Min.Value).Min.Exclusive = fs.Min.Exclusive }},
+		{"max", fs.Max != nil, func() { f.AtMost(fs.Max.Value).Max.Exclusive = fs.Max.Exclusive }},
+		{"optional", fs.Optional, func() { f.Optional(fs.Implied) }},
+		{"allow_hyphen", fs.AllowHyphen, func() { f.AllowHyphenValues() }},
+		{"int_literals", fs.IntLiterals, func() { f.AcceptLiterals() }},
+		{"absolute", fs.Absolute, func() { f.Absolute() }},
+		{"from_file", fs.FromFile, func() { f.FromFile() }},
+	}
+	for _, s := range steps {
+		if !s.set {
+			continue
+		}
+		if err := catch(s.apply); err != nil {
+			return nil, fieldErr(s.field, "%v", err)
+		}
+	}
+	if fs.Implied != "" && !fs.Optional {
+		return nil, fieldErr("implied", "only valid for optional flags")
+	}
+
+	for j, name := range fs.Check {
+		bit := slices.Index(pathCheckNames, name)
+		if bit < 0 {
+			return nil, fieldErr(fmt.Sprintf("check[%d]", j), "unknown check %q", name)
+		}
+		if err := catch(func() { f.Check(PathCheck(1) << bit) }); err != nil {
+			return nil, fieldErr(fmt.Sprintf("check[%d]", j), "%v", err)
+		}
+	}
+
+	if fs.Mode != "" {
+		if t != FileType {
+			return nil, fieldErr("mode", "only valid for file flags")
+		}
+		mode, ok := parseFileMode(fs.Mode)
+		if !ok {
+			return nil, fieldErr("mode", "unknown mode %q", fs.Mode)
+		}
+		f.FileMode = mode
+	}
+	return f, nil
+}
+
+// specDefault decodes a JSON default into the Go type of the flag, zero when absent
+func specDefault(t FlagType, raw json.RawMessage) (any, error) {
+	rt, ok := basicTypes[t]
+	if !ok {
+		rt = basicTypes[StringType]
+	}
+	v := reflect.New(rt).Elem()
+	if len(raw) == 0 {
+		return v.Interface(), nil
+	}
+
+	var text string
+	switch {
+	case rt.Kind() == reflect.Bool:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return nil, fmt.Errorf("expected boolean, got %s", raw)
+		}
+		text = strconv.FormatBool(b)
+	case rt.Kind() == reflect.String:
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, fmt.Errorf("expected string, got %s", raw)
+		}
+	default:
+		var n json.Number
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return nil, fmt.Errorf("expected number, got %s", raw)
+		}
+		text = n.String()
+	}
+
+	if err := convertValue(v, text); err != nil {
+		return nil, fmt.Errorf("invalid %s %s: %v", t, text, err)
+	}
+	return v.Interface(), nil
+}
+
+// parseFlagType returns the flag type with the given name
+func parseFlagType(name string) (FlagType, bool) {
+	for t := BoolType; t <= EnumType; t++ {
+		if t.String() == name {
+			return t, true
+		}
+	}
+	return BoolType, false
+}
+
+// parseFileMode returns the file mode with the given name
+func parseFileMode(name string) (FileMode, bool) {
+	for m, n := range fileModeNames {
+		if n == name {
+			return m, true
+		}
+	}
+	return ReadMode, false
+}
+
+// parseInterspersal returns the interspersal with the given name, empty meaning inherit
+func parseInterspersal(path, name string) (Interspersal, error) {
+	if name == "" {
+		return InheritInterspersal, nil
+	}
+	for i, n := range interspersalNames {
+		if n == name {
+			return i, nil
+		}
+	}
+	return InheritInterspersal, &SpecError{Path: path, Err: fmt.Errorf("unknown interspersal %q", name)}
+}
+
+// jsonPath converts a decoder field path such as "commands.1.flags" to "$.commands[1].flags"
+func jsonPath(field string) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, part := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(part); err == nil {
+			b.WriteString("[" + part + "]")
+			continue
+		}
+		b.WriteString("." + part)
+	}
+	return b.String()
+}
+
+// catch runs fn and returns its panic, if any, as an error
+func catch(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	fn()
+	return nil
+}
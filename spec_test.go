@@ -0,0 +1,156 @@
+package paws
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSpecRoundTrip(t *testing.T) {
+	parser := New()
+	parser.AllowAbbrev = true
+	parser.Interspersal = NonInterspersed
+	parser.AddFlags(
+		Paw[bool]("verbose", "v").Help("verbose output"),
+		Paw[string]("color").Optional("always").Meta("WHEN").Default("auto").Env("COLOR"),
+	)
+	parser.AddCommand([]string{"build"}, []*Flag{
+		Paw[int]("jobs", "j").Range(1, 64).Default(4),
+		Paw[float64]("ratio").Above(0).AtMost(1),
+		Paw[uint8]("level").Choices("1", "2", "3").AcceptLiterals(),
+		Paw[string]("mode").Choices("fast", "slow").Required(),
+		Enum("format", []EnumValue[int]{{Token: "json", Value: 1, Desc: "JSON output"}, {Token: "text", Aliases: []string{"txt"}, Value: 2}}).IgnoreCase().Default("text"),
+		Path("dir").Check(PathIsDir).Absolute(),
+		OutFile("out", "o"),
+		Paw[string]("pattern").AllowHyphenValues(),
+		Paw[string]("token").Secret().FromFile(),
+	}).Help("Build the project").Args(Positional("target").Variadic())
+
+	data, err := Export(parser)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	imported, err := FromSpec(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("FromSpec() error = %v\n%s", err, data)
+	}
+
+	again, err := Export(imported)
+	if err != nil {
+		t.Fatalf("Export() of imported parser error = %v", err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Errorf("round trip changed the spec:\n%s\nvs\n%s", data, again)
+	}
+
+	args := []string{"build", "--mode", "fast", "-j", "8", "--level", "0x2", "--format", "TXT", "--color", "a"}
+	for _, p := range []*Parser{parser, imported} {
+		result, err := p.Parse(args)
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if got := MustGet[int](result, "jobs"); got != 8 {
+			t.Errorf("jobs = %d, want 8", got)
+		}
+		if got := MustGet[uint8](result, "level"); got != 2 {
+			t.Errorf("level = %d, want 2", got)
+		}
+		if got := result.String("format"); got != "text" {
+			t.Errorf("format = %q, want text", got)
+		}
+	}
+
+	if got := MustGet[int](mustParse(t, imported, "build", "--mode=slow"), "jobs"); got != 4 {
+		t.Errorf("imported default jobs = %d, want 4", got)
+	}
+	if got := MustGetEnum[string](mustParse(t, imported, "build", "--mode=slow"), "format"); got != "text" {
+		t.Errorf("imported enum value = %q, want text", got)
+	}
+	if _, err := imported.Parse([]string{"build", "--mode", "slow", "--jobs", "100"}); !errors.Is(err, ErrFlagValue) {
+		t.Errorf("imported range not enforced, error = %v", err)
+	}
+}
+
+func mustParse(t *testing.T, p *Parser, args ...string) *ParseResult {
+	t.Helper()
+	result, err := p.Parse(args)
+	if err != nil {
+		t.Fatalf("Parse(%v) error = %v", args, err)
+	}
+	return result
+}
+
+func TestFromSpecErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		path string
+	}{
+		{"version", `{"version": 2}`, "$.version"},
+		{"unknown type", `{"version": 1, "commands": [{"path": ["a"]}, {"path": ["b"], "flags": [{"name": "x", "type": "int"}, {"name": "y", "type": "integer"}]}]}`, "$.commands[1].flags[1].type"},
+		{"json type", `{"version": 1, "flags": [{"name": "x", "type": 3}]}`, "$.flags[0].type"},
+		{"default type", `{"version": 1, "flags": [{"name": "x", "type": "int", "default": "five"}]}`, "$.flags[0].default"},
+		{"default overflow", `{"version": 1, "flags": [{"name": "x", "type": "int8", "default": 300}]}`, "$.flags[0].default"},
+		{"range on string", `{"version": 1, "flags": [{"name": "x", "type": "string", "min": {"value": 1}}]}`, "$.flags[0].min"},
+		{"bad choice", `{"version": 1, "flags": [{"name": "x", "type": "int", "choices": ["one"]}]}`, "$.flags[0].choices"},
+		{"check", `{"version": 1, "flags": [{"name": "x", "type": "path", "check": ["dir", "big"]}]}`, "$.flags[0].check[1]"},
+		{"duplicate", `{"version": 1, "flags": [{"name": "x", "type": "bool"}, {"name": "x", "type": "bool"}]}`, "$.flags[1]"},
+		{"default choice", `{"version": 1, "commands": [{"path": ["a"], "flags": [{"name": "m", "type": "string", "choices": ["a"], "default": "b"}]}]}`, "$.commands[0].flags[0]"},
+		{"zero default out of range", `{"version": 1, "flags": [{"name": "x", "type": "int", "default": 0, "min": {"value": 1}}]}`, "$.flags[0]"},
+		{"duplicate command", `{"version": 1, "commands": [{"path": ["a"]}, {"path": ["a"]}]}`, "$.commands[1].path"},
+		{"missing path", `{"version": 1, "commands": [{"path": ["a"]}, {"flags": [{"name": "x", "type": "bool"}]}]}`, "$.commands[1].path"},
+		{"empty path word", `{"version": 1, "commands": [{"path": ["a", ""]}]}`, "$.commands[0].path"},
+		{"duplicate alias", `{"version": 1, "flags": [{"name": "x", "type": "bool"}, {"name": "y", "aliases": ["x"], "type": "bool"}]}`, "$.flags[1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromSpec(strings.NewReader(tt.spec))
+			if !errors.Is(err, ErrDefinition) {
+				t.Fatalf("FromSpec() error = %v, want ErrDefinition", err)
+			}
+			var se *SpecError
+			if !errors.As(err, &se) || se.Path != tt.path {
+				t.Errorf("FromSpec() error = %v, want path %s", err, tt.path)
+			}
+		})
+	}
+}
+
+func TestSpecWarnings(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[bool]("verbose", "v"))
+	parser.AddCommand([]string{"build"}, []*Flag{Paw[int]("jobs", "j"), Paw[bool]("verbose")})
+
+	data, err := Export(parser)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if _, err := FromSpec(bytes.NewReader(data)); err != nil {
+		t.Fatalf("FromSpec() error = %v, want shadowing to be a warning", err)
+	}
+
+	s, err := ReadSpec(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadSpec() error = %v", err)
+	}
+	warnings, err := s.Warnings()
+	if err != nil {
+		t.Fatalf("Warnings() error = %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Path != "$.commands[0].flags[1]" {
+		t.Errorf("Warnings() = %v, want shadowed $.commands[0].flags[1]", warnings)
+	}
+}
+
+func TestExportDefaultMismatch(t *testing.T) {
+	parser := New()
+	parser.AddFlags(Paw[int8]("level").Default("high"))
+
+	var se *SpecError
+	if _, err := Export(parser); !errors.As(err, &se) || se.Path != "$.flags[0].default" {
+		t.Errorf("Export() error = %v, want path $.flags[0].default", err)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd || windows)
+
+package paws
+
+import (
+	"errors"
+	"os"
+)
+
+// isTerminal reports whether f is a character device, which is
+// the closest check available without terminal ioctls
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// readPassword fails, as echo cannot be turned off on this platform
+// and the input would be shown
+func readPassword(f *os.File) (string, error) {
+	return "", errors.New("cannot read secret input without echo on this platform")
+}
@@ -0,0 +1,40 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package paws
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// isTerminal reports whether f is a terminal
+func isTerminal(f *os.File) bool {
+	var t syscall.Termios
+	return ioctl(f.Fd(), ioctlGetTermios, &t) == nil
+}
+
+// readPassword reads a line from the terminal f without echoing it
+func readPassword(f *os.File) (string, error) {
+	var old syscall.Termios
+	if err := ioctl(f.Fd(), ioctlGetTermios, &old); err != nil {
+		return "", err
+	}
+
+	t := old
+	t.Lflag &^= syscall.ECHO
+	t.Lflag |= syscall.ICANON | syscall.ISIG
+	if err := ioctl(f.Fd(), ioctlSetTermios, &t); err != nil {
+		return "", err
+	}
+	defer ioctl(f.Fd(), ioctlSetTermios, &old)
+
+	return readLine(f)
+}
+
+func ioctl(fd uintptr, req uintptr, t *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(unsafe.Pointer(t))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package paws
+
+import (
+	"os"
+	"syscall"
+)
+
+// enableEchoInput is ENABLE_ECHO_INPUT of the console input mode
+const enableEchoInput = 0x0004
+
+var procSetConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// isTerminal reports whether f is a console
+func isTerminal(f *os.File) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(f.Fd()), &mode) == nil
+}
+
+// readPassword reads a line from the console f without echoing it
+func readPassword(f *os.File) (string, error) {
+	h := syscall.Handle(f.Fd())
+	var old uint32
+	if err := syscall.GetConsoleMode(h, &old); err != nil {
+		return "", err
+	}
+
+	if err := setConsoleMode(h, old&^enableEchoInput); err != nil {
+		return "", err
+	}
+	defer setConsoleMode(h, old)
+
+	return readLine(f)
+}
+
+func setConsoleMode(h syscall.Handle, mode uint32) error {
+	if r, _, err := procSetConsoleMode.Call(uintptr(h), uintptr(mode)); r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,455 @@
+package paws
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// AddUsage registers a command declared by a usage pattern, e.g.
+//
+//	cp [-r|--recursive] [-n <count:int>] <src>... <dst>
+//
+// Leading words form the command path. Flags in brackets are optional,
+// others required. A placeholder right after a flag is its value, typed
+// with ":int", ":float", ":path" and the like, string by default.
+// Other placeholders are positionals, "..." makes them variadic.
+func (p *Parser) AddUsage(pattern string) (*CommandDef, error) {
+	up := &usageParser{text: pattern, required: true}
+	if err := up.parse(0); err != nil {
+		return nil, err
+	}
+	if len(up.path) == 0 {
+		return nil, up.errAt(0, "usage pattern must start with the command name")
+	}
+	return p.AddCommand(up.path, up.flags).Args(up.args...), nil
+}
+
+// FromHelp builds a parser from a docopt style help text. Lines of the
+// "Usage:" section start with the program name followed by the command
+// path and use the AddUsage syntax. Flags of the "Options:" section are
+// global, each line holding the flag names and value placeholder, then
+// the help text after two spaces, which may end with "[default: value]".
+//
+//	Usage:
+//	  tool add [--force] <name>
+//	  tool list [options]
+//
+//	Options:
+//	  -v, --verbose         Verbose output.
+//	  -n, --num=<n:int>     Number of items [default: 10].
+func FromHelp(text string) (*Parser, error) {
+	p := New()
+
+	type line struct {
+		no    int
+		text  string
+		start int
+	}
+	var usage, options []line
+
+	section := ""
+	for i, l := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(l)
+		start := strings.Index(l, trimmed)
+		lower := strings.ToLower(trimmed)
+
+		switch {
+		case strings.HasPrefix(lower, "usage:"):
+			section = "usage"
+			if rest := strings.TrimSpace(trimmed[len("usage:"):]); rest != "" {
+				usage = append(usage, line{i + 1, l, strings.Index(l, rest)})
+			}
+		case strings.HasPrefix(lower, "options:"):
+			section = "options"
+		case trimmed == "":
+			if section == "usage" {
+				section = ""
+			}
+		case start == 0 && strings.HasSuffix(trimmed, ":"):
+			section = ""
+		case section == "usage":
+			usage = append(usage, line{i + 1, l, start})
+		case section == "options":
+			options = append(options, line{i + 1, l, start})
+		}
+	}
+
+	if len(usage) == 0 {
+		return nil, fmt.Errorf("%w: help text has no usage section", ErrDefinition)
+	}
+
+	var last *Flag
+	for _, l := range options {
+		if l.text[l.start] != '-' {
+			// Continuation of the previous help text
+			if last != nil {
+				up := &usageParser{text: l.text, line: l.no}
+				if err := up.describe(last, l.start); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		end := len(l.text)
+		if i := strings.Index(l.text[l.start:], "  "); i >= 0 {
+			end = l.start + i
+		}
+		up := &usageParser{text: l.text, line: l.no, option: true}
+		f, err := up.optionLine(l.start, end)
+		if err != nil {
+			return nil, err
+		}
+		if err := up.describe(f, end); err != nil {
+			return nil, err
+		}
+		p.Flags = append(p.Flags, f)
+		last = f
+	}
+
+	global := func(name string) bool {
+		return slices.ContainsFunc(p.Flags, func(f *Flag) bool {
+			return f.Name == name || slices.Contains(f.Aliases, name)
+		})
+	}
+
+	for _, l := range usage {
+		up := &usageParser{text: l.text, line: l.no, required: true, known: global}
+		if err := up.parse(l.start); err != nil {
+			return nil, err
+		}
+		if len(up.path) == 0 {
+			return nil, up.errAt(l.start, "usage line must start with the program name")
+		}
+
+		path := up.path[1:]
+		if len(path) > 0 {
+			p.Commands = append(p.Commands, &CommandDef{Path: path, Flags: up.flags, Positionals: up.args})
+			continue
+		}
+		if len(up.args) > 0 {
+			return nil, up.errAt(up.argPos, "positional arguments need a command")
+		}
+		for _, f := range up.flags {
+			f.IsRequired = false
+			p.Flags = append(p.Flags, f)
+		}
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// usageToken is a lexical element of a usage pattern
+type usageToken struct {
+	kind byte   // '[', ']', '|', '<', '.' for "...", 'w' for words, 0 at the end
+	text string // Word or placeholder contents
+	pos  int    // Byte offset in the line
+}
+
+// usageParser parses one usage pattern or option line
+type usageParser struct {
+	text     string
+	line     int
+	required bool              // Whether flags outside brackets are required
+	option   bool              // Whether parsing an option line, allowing "-o FILE" and commas
+	known    func(string) bool // Reports flags declared elsewhere, nil when none
+
+	toks []usageToken
+	i    int
+
+	path   []string
+	flags  []*Flag
+	args   []*Arg
+	argPos int
+}
+
+func (up *usageParser) errAt(pos int, format string, args ...any) *SyntaxError {
+	return &SyntaxError{Text: up.text, Line: up.line, Column: pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+// lex splits text[start:end] into tokens
+func (up *usageParser) lex(start, end int) error {
+	s := up.text
+	for i := start; i < end; {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || (c == ',' && up.option):
+			i++
+		case c == '[' || c == ']' || c == '|':
+			up.toks = append(up.toks, usageToken{kind: c, pos: i})
+			i++
+		case c == '(' || c == ')':
+			return up.errAt(i, "groups with %q are not supported", c)
+		case c == '<':
+			j := strings.IndexByte(s[i:end], '>')
+			if j < 0 {
+				return up.errAt(i, "missing '>'")
+			}
+			up.toks = append(up.toks, usageToken{kind: '<', text: s[i+1 : i+j], pos: i})
+			i += j + 1
+		case strings.HasPrefix(s[i:end], "..."):
+			up.toks = append(up.toks, usageToken{kind: '.', pos: i})
+			i += 3
+		default:
+			j := i
+			for j < end && !strings.ContainsRune(" \t[]|<()", rune(s[j])) && !strings.HasPrefix(s[j:end], "...") && !(up.option && s[j] == ',') {
+				j++
+			}
+			up.toks = append(up.toks, usageToken{kind: 'w', text: s[i:j], pos: i})
+			i = j
+		}
+	}
+	up.toks = append(up.toks, usageToken{pos: end})
+	return nil
+}
+
+func (up *usageParser) peek() usageToken { return up.toks[up.i] }
+
+func (up *usageParser) next() usageToken {
+	t := up.toks[up.i]
+	if t.kind != 0 {
+		up.i++
+	}
+	return t
+}
+
+// parse reads the command words followed by flags and positionals
+func (up *usageParser) parse(start int) error {
+	if err := up.lex(start, len(up.text)); err != nil {
+		return err
+	}
+	for t := up.peek(); t.kind == 'w' && !strings.HasPrefix(t.text, "-"); t = up.peek() {
+		up.path = append(up.path, up.next().text)
+	}
+	return up.elements(false, nil)
+}
+
+// elements reads flags and positionals until the closing bracket of open, or the end
+func (up *usageParser) elements(optional bool, open *usageToken) error {
+	for {
+		t := up.peek()
+		switch t.kind {
+		case 0:
+			if open != nil {
+				return up.errAt(open.pos, "missing ']'")
+			}
+			return nil
+
+		case ']':
+			if open == nil {
+				return up.errAt(t.pos, "unexpected ']'")
+			}
+			up.next()
+			return nil
+
+		case '[':
+			up.next()
+			if err := up.elements(true, &t); err != nil {
+				return err
+			}
+
+		case '<':
+			up.next()
+			name, _, typed := strings.Cut(t.text, ":")
+			if typed {
+				return up.errAt(t.pos+len(name)+1, "positional arguments have no type")
+			}
+			if name == "" {
+				return up.errAt(t.pos, "empty placeholder")
+			}
+			a := &Arg{Name: name, IsOptional: optional}
+			if up.peek().kind == '.' {
+				up.next()
+				a.IsVariadic = true
+			}
+			if len(up.args) == 0 {
+				up.argPos = t.pos
+			}
+			up.args = append(up.args, a)
+
+		case 'w':
+			if t.text == "options" && optional {
+				up.next()
+				continue
+			}
+			if !strings.HasPrefix(t.text, "-") {
+				return up.errAt(t.pos, "unexpected word %q, command words must come first", t.text)
+			}
+			f, err := up.flag()
+			if err != nil {
+				return err
+			}
+			if f == nil {
+				continue
+			}
+			f.IsRequired = up.required && !optional
+			up.flags = append(up.flags, f)
+
+		case '|':
+			return up.errAt(t.pos, "'|' must separate flag names")
+
+		case '.':
+			return up.errAt(t.pos, "'...' must follow a positional argument")
+		}
+	}
+}
+
+// flag reads alternative flag names and an optional value placeholder.
+// It returns nil when the flag is declared elsewhere.
+func (up *usageParser) flag() (*Flag, error) {
+	var names []string
+	var meta, typ string
+	var metaPos int
+
+	setMeta := func(text string, pos int) {
+		if meta == "" {
+			meta, metaPos = text, pos
+		}
+	}
+
+	for expectName := true; ; {
+		t := up.peek()
+		switch {
+		case expectName:
+			up.next()
+			if t.kind != 'w' || !strings.HasPrefix(t.text, "-") {
+				return nil, up.errAt(t.pos, "expected a flag name")
+			}
+			word, value, hasValue := strings.Cut(t.text, "=")
+			name := strings.TrimPrefix(strings.TrimPrefix(word, "-"), "-")
+			if name == "" || (!strings.HasPrefix(word, "--") && len(name) > 1) {
+				return nil, up.errAt(t.pos, "invalid flag %q, use -x or --name", word)
+			}
+			names = append(names, name)
+
+			switch {
+			case hasValue && value != "":
+				setMeta(value, t.pos+len(word)+1)
+			case hasValue && up.peek().kind != '<':
+				return nil, up.errAt(t.pos+len(t.text), "expected <value> after '='")
+			case hasValue:
+				ph := up.next()
+				setMeta(ph.text, ph.pos+1)
+			}
+			expectName = false
+			continue
+
+		case t.kind == '|':
+			up.next()
+			expectName = true
+			continue
+
+		case up.option && t.kind == 'w' && strings.HasPrefix(t.text, "-"):
+			expectName = true
+			continue
+
+		case meta == "" && t.kind == '<':
+			up.next()
+			setMeta(t.text, t.pos+1)
+			continue
+
+		case meta == "" && up.option && t.kind == 'w' && isUpperWord(t.text):
+			up.next()
+			setMeta(t.text, t.pos)
+			continue
+		}
+		break
+	}
+
+	if t := up.peek(); t.kind == '.' {
+		return nil, up.errAt(t.pos, "flags cannot be repeated")
+	}
+
+	if up.known != nil && slices.ContainsFunc(names, up.known) {
+		return nil, nil
+	}
+
+	f := &Flag{Name: names[0], Type: BoolType, DefValue: false}
+	for _, n := range names[1:] {
+		if len(n) > 1 && len(f.Name) == 1 {
+			f.Aliases = append(f.Aliases, f.Name)
+			f.Name = n
+			continue
+		}
+		f.Aliases = append(f.Aliases, n)
+	}
+
+	if meta != "" {
+		meta, typ, _ = strings.Cut(meta, ":")
+		if meta == "" {
+			return nil, up.errAt(metaPos, "empty placeholder")
+		}
+		f.Type = StringType
+		if typ != "" {
+			t, ok := parseFlagType(typ)
+			if !ok || t == EnumType {
+				return nil, up.errAt(metaPos+len(meta)+1, "unknown type %q", typ)
+			}
+			f.Type = t
+		}
+		f.DefValue, _ = specDefault(f.Type, nil)
+		f.MetaVar = strings.ToUpper(meta)
+	}
+	return f, nil
+}
+
+// optionLine parses the flag part of an option line, text[start:end]
+func (up *usageParser) optionLine(start, end int) (*Flag, error) {
+	if err := up.lex(start, end); err != nil {
+		return nil, err
+	}
+	f, err := up.flag()
+	if err != nil {
+		return nil, err
+	}
+	if t := up.peek(); t.kind != 0 {
+		return nil, up.errAt(t.pos, "unexpected %q, separate the help text with two spaces", up.text[t.pos:end])
+	}
+	return f, nil
+}
+
+var defaultPattern = regexp.MustCompile(`(?i)\s*\[default:\s*([^\]]*)\]\.?`)
+
+// describe appends the option line after pos to the help text of f
+// and sets the default it holds, if any
+func (up *usageParser) describe(f *Flag, pos int) error {
+	desc := up.text[pos:]
+	m := defaultPattern.FindStringSubmatchIndex(desc)
+	if m != nil {
+		desc = desc[:m[0]] + desc[m[1]:]
+	}
+	f.HelpText = strings.TrimSpace(f.HelpText + " " + strings.TrimSpace(desc))
+	if m == nil {
+		return nil
+	}
+
+	desc = up.text[pos:]
+	value := strings.TrimSpace(desc[m[2]:m[3]])
+	if f.Type == BoolType {
+		return up.errAt(pos+m[0], "flag %s takes no value and cannot have a default", f.Name)
+	}
+
+	rt, ok := basicTypes[f.Type]
+	if !ok {
+		f.DefValue = value
+		return nil
+	}
+	v := reflect.New(rt).Elem()
+	if err := convertValue(v, value); err != nil {
+		return up.errAt(pos+m[2], "invalid default %q for %s flag", value, f.Type)
+	}
+	f.DefValue = v.Interface()
+	return nil
+}
+
+// isUpperWord reports whether s is a placeholder such as "FILE"
+func isUpperWord(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return !unicode.IsUpper(r) && r != '_' && !unicode.IsDigit(r) }) < 0
+}
@@ -0,0 +1,170 @@
+package paws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAddUsage(t *testing.T) {
+	parser := New()
+	cmd, err := parser.AddUsage("cp [-r|--recursive] [-n <count:int>] --mode=<m> <src>... [<dst>]")
+	if err != nil {
+		t.Fatalf("AddUsage() error = %v", err)
+	}
+
+	if got := cmd.Name(); got != "cp" {
+		t.Errorf("command = %q, want cp", got)
+	}
+
+	want := []struct {
+		name     string
+		aliases  []string
+		typ      FlagType
+		required bool
+	}{
+		{"recursive", []string{"r"}, BoolType, false},
+		{"n", nil, IntType, false},
+		{"mode", nil, StringType, true},
+	}
+	if len(cmd.Flags) != len(want) {
+		t.Fatalf("AddUsage() created %d flags, want %d", len(cmd.Flags), len(want))
+	}
+	for i, w := range want {
+		f := cmd.Flags[i]
+		if f.Name != w.name || strings.Join(f.Aliases, ",") != strings.Join(w.aliases, ",") || f.Type != w.typ || f.IsRequired != w.required {
+			t.Errorf("flag %d = %s %v %s required=%v, want %+v", i, f.Name, f.Aliases, f.Type, f.IsRequired, w)
+		}
+	}
+
+	var args []string
+	for _, a := range cmd.Positionals {
+		args = append(args, a.Usage())
+	}
+	if got := strings.Join(args, " "); got != "<src>... [dst]" {
+		t.Errorf("positionals = %q, want <src>... [dst]", got)
+	}
+
+	result, err := parser.Parse([]string{"cp", "-r", "-n", "3", "--mode", "x", "a", "b"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !result.Bool("recursive") || MustGet[int](result, "n") != 3 {
+		t.Errorf("Parse() flags = %v", result.Flags)
+	}
+}
+
+func TestAddUsageErrors(t *testing.T) {
+	tests := []struct {
+		pattern string
+		column  int
+		msg     string
+	}{
+		{"cp [-r <src>", 4, "missing ']'"},
+		{"cp -n <count:integer>", 14, "unknown type"},
+		{"cp <src:path>", 8, "positional arguments have no type"},
+		{"cp <src", 4, "missing '>'"},
+		{"cp <src> more", 10, "command words must come first"},
+		{"cp -recursive", 4, "invalid flag"},
+		{"cp -v...", 6, "flags cannot be repeated"},
+		{"[-v]", 1, "must start with the command name"},
+		{"cp (-a | -b)", 4, "not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, err := New().AddUsage(tt.pattern)
+			var se *SyntaxError
+			if !errors.As(err, &se) {
+				t.Fatalf("AddUsage() error = %v, want SyntaxError", err)
+			}
+			if se.Column != tt.column || !strings.Contains(se.Msg, tt.msg) {
+				t.Errorf("AddUsage() error = %q at column %d, want %q at column %d", se.Msg, se.Column, tt.msg, tt.column)
+			}
+			if !errors.Is(err, ErrDefinition) {
+				t.Error("SyntaxError should unwrap to ErrDefinition")
+			}
+		})
+	}
+}
+
+func TestSyntaxErrorCaret(t *testing.T) {
+	_, err := New().AddUsage("cp <src")
+	want := "invalid definition: missing '>' at column 4\n  cp <src\n     ^"
+	if err == nil || err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err, want)
+	}
+}
+
+const helpText = `Tool manages things.
+
+Usage:
+  tool add [--force] <name>
+  tool list [options]
+  tool -h | --help
+
+Options:
+  -v, --verbose         Verbose output.
+  -n, --num=<n:int>     Number of items
+                        to show [default: 10].
+  -o FILE --output=FILE  Output file.
+`
+
+func TestFromHelp(t *testing.T) {
+	parser, err := FromHelp(helpText)
+	if err != nil {
+		t.Fatalf("FromHelp() error = %v", err)
+	}
+
+	if len(parser.Commands) != 2 {
+		t.Fatalf("FromHelp() created %d commands, want 2", len(parser.Commands))
+	}
+
+	var globals []string
+	for _, f := range parser.Flags {
+		globals = append(globals, f.Usage())
+	}
+	want := "-v, --verbose|-n, --num N|-o, --output FILE|-h, --help"
+	if got := strings.Join(globals, "|"); got != want {
+		t.Errorf("global flags = %q, want %q", got, want)
+	}
+
+	num := parser.Flags[1]
+	if num.DefValue != 10 || num.HelpText != "Number of items to show" {
+		t.Errorf("num flag default = %v, help = %q", num.DefValue, num.HelpText)
+	}
+
+	result, err := parser.Parse([]string{"add", "--force", "-v", "x"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !result.Bool("force") || !result.Bool("verbose") || MustGet[int](result, "num") != 10 {
+		t.Errorf("Parse() flags = %v", result.Flags)
+	}
+}
+
+func TestFromHelpErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		line int
+	}{
+		{"bad default", "Usage:\n  tool a\n\nOptions:\n  --num=<n:int>  Count [default: many].", 5},
+		{"root positional", "Usage:\n  tool <file>", 2},
+		{"option junk", "Usage:\n  tool a\nOptions:\n  -v verbose output", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromHelp(tt.text)
+			var se *SyntaxError
+			if !errors.As(err, &se) || se.Line != tt.line {
+				t.Errorf("FromHelp() error = %v, want SyntaxError on line %d", err, tt.line)
+			}
+		})
+	}
+
+	if _, err := FromHelp("no usage here"); !errors.Is(err, ErrDefinition) {
+		t.Errorf("FromHelp() without usage error = %v, want ErrDefinition", err)
+	}
+}
@@ -0,0 +1,121 @@
+package paws
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	errUnterminatedQuote = errors.New("unterminated quote")
+	errTrailingEscape    = errors.New("trailing backslash")
+)
+
+// Split splits a command line into words the way a POSIX shell does.
+// Single quotes keep everything literally, double quotes let a backslash
+// escape $, `, ", \ and newlines, and outside quotes a backslash escapes
+// any character. A backslash before a newline continues the line.
+// Variables, globs and other expansions are not performed.
+func Split(s string) ([]string, error) {
+	words, _, err := splitWords(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+	return words, nil
+}
+
+// Join quotes words so that Split, or a POSIX shell, reads them back unchanged.
+// Words made only of safe characters are left as is, others are single quoted.
+func Join(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = quoteWord(w)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// quoteWord single quotes w unless it only holds characters no shell treats specially
+func quoteWord(w string) string {
+	if w != "" && strings.IndexFunc(w, func(r rune) bool { return !isSafeRune(r) }) < 0 {
+		return w
+	}
+	return "'" + strings.ReplaceAll(w, "'", `'\''`) + "'"
+}
+
+func isSafeRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%+=:,./_-", r)
+}
+
+// splitWords splits s into words the way Split does. On error, words holds
+// what was read so far, including the unfinished last word. open reports
+// whether s ends inside a word rather than after a separator.
+func splitWords(s string) (words []string, open bool, err error) {
+	var (
+		b      strings.Builder
+		inWord bool
+	)
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, b.String())
+				b.Reset()
+				inWord = false
+			}
+
+		case c == '\\':
+			if i+1 == len(s) {
+				return append(words, b.String()), true, errTrailingEscape
+			}
+			i++
+			// Line continuation
+			if s[i] == '\n' {
+				continue
+			}
+			inWord = true
+			b.WriteByte(s[i])
+
+		case c == '\'':
+			inWord = true
+			j := strings.IndexByte(s[i+1:], '\'')
+			if j < 0 {
+				b.WriteString(s[i+1:])
+				return append(words, b.String()), true, errUnterminatedQuote
+			}
+			b.WriteString(s[i+1 : i+1+j])
+			i += j + 1
+
+		case c == '"':
+			inWord = true
+			closed := false
+			for i++; i < len(s); i++ {
+				if s[i] == '"' {
+					closed = true
+					break
+				}
+				// Backslash only escapes characters special inside double quotes
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				b.WriteByte(s[i])
+			}
+			if !closed {
+				return append(words, b.String()), true, errUnterminatedQuote
+			}
+
+		default:
+			inWord = true
+			b.WriteByte(c)
+		}
+	}
+
+	if inWord {
+		words = append(words, b.String())
+	}
+	return words, inWord, nil
+}
@@ -0,0 +1,111 @@
+package paws
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+		err  error
+	}{
+		{`a  b	c`, []string{"a", "b", "c"}, nil},
+		{`'a b' "c d"`, []string{"a b", "c d"}, nil},
+		{`a\ b "x\"y" "\n"`, []string{"a b", `x"y`, `\n`}, nil},
+		{`pre'mid'"end"`, []string{"premidend"}, nil},
+		{`'' ""`, []string{"", ""}, nil},
+		{`'open`, []string{"open"}, errUnterminatedQuote},
+		{`a\`, []string{"a"}, errTrailingEscape},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, _, err := splitWords(tt.in)
+			if !reflect.DeepEqual(got, tt.want) || err != tt.err {
+				t.Errorf("splitWords(%q) = %q, %v, want %q, %v", tt.in, got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"deploy --env prod \\\n  --force", []string{"deploy", "--env", "prod", "--force"}},
+		{"a\\\nb", []string{"ab"}},
+		{"\"multi \\\nline\"", []string{"multi line"}},
+		{"'keep \\\nthis'", []string{"keep \\\nthis"}},
+		{"\"two\nlines\"", []string{"two\nlines"}},
+		{"  ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Split(tt.in)
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+			}
+		})
+	}
+
+	for _, in := range []string{`"open`, `'open`, `end\`} {
+		if _, err := Split(in); !errors.Is(err, ErrParse) {
+			t.Errorf("Split(%q) error = %v, want ErrParse", in, err)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want string
+	}{
+		{[]string{"git", "commit", "-m", "fix: it's done"}, `git commit -m 'fix: it'\''s done'`},
+		{[]string{"--path=/tmp/a.txt", "user@host:22"}, "--path=/tmp/a.txt user@host:22"},
+		{[]string{"", "a b", "$HOME", "*", "~", "#x"}, `'' 'a b' '$HOME' '*' '~' '#x'`},
+		{[]string{"line\nbreak", `back\slash`}, "'line\nbreak' 'back\\slash'"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got := Join(tt.in)
+			if got != tt.want {
+				t.Errorf("Join(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+			back, err := Split(got)
+			if err != nil || (len(tt.in) > 0 && !reflect.DeepEqual(back, tt.in)) {
+				t.Errorf("Split(Join(%q)) = %q, %v", tt.in, back, err)
+			}
+		})
+	}
+}
+
+func TestShellContinuation(t *testing.T) {
+	parser := New()
+	cmd := parser.AddCommand([]string{"echo"}, nil)
+
+	var got []string
+	var out strings.Builder
+	sh := NewShell(parser, strings.NewReader("echo one \\\ntwo \"three\nfour\"\n"), &out)
+	sh.Handle(cmd, func(r *ParseResult) error {
+		got = r.Positional
+		return nil
+	})
+
+	if err := sh.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := []string{"one", "two", "three\nfour"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Positional = %q, want %q", got, want)
+	}
+	if strings.Count(out.String(), "... ") != 2 {
+		t.Errorf("expected two continuation prompts in %q", out.String())
+	}
+}